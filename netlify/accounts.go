@@ -0,0 +1,71 @@
+package netlify
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Caches accounts by slug so that resources sharing an account only look it
+// up once. The cache lives on the Meta, so it never outlives a single run.
+type accountCache struct {
+	mu       sync.Mutex
+	accounts map[string]*models.AccountMembership
+	fetch    func(slug string) (*models.AccountMembership, error)
+}
+
+func newAccountCache(fetch func(slug string) (*models.AccountMembership, error)) *accountCache {
+	return &accountCache{
+		accounts: map[string]*models.AccountMembership{},
+		fetch:    fetch,
+	}
+}
+
+// Returns the account with the given slug, fetching it on first use. The
+// lock is held while fetching so concurrent resources don't race to fetch
+// the same account.
+func (c *accountCache) get(slug string) (*models.AccountMembership, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if account, ok := c.accounts[slug]; ok {
+		return account, nil
+	}
+
+	account, err := c.fetch(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	c.accounts[slug] = account
+	return account, nil
+}
+
+// Returns the account with the given slug.
+func (m *Meta) Account(slug string) (*models.AccountMembership, error) {
+	return m.accounts.get(slug)
+}
+
+// Returns slug, or the provider's default_account_slug if slug is empty.
+func (m *Meta) accountSlug(slug string) string {
+	if slug == "" {
+		return m.defaultAccountSlug
+	}
+	return slug
+}
+
+func (m *Meta) fetchAccount(slug string) (*models.AccountMembership, error) {
+	params := operations.NewGetAccountParams()
+	params.AccountID = slug
+	resp, err := m.Operations.GetAccount(params, m.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Payload) == 0 {
+		return nil, fmt.Errorf("Account %s not found", slug)
+	}
+	return resp.Payload[0], nil
+}
@@ -0,0 +1,48 @@
+package netlify
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/netlify/open-api/v2/go/models"
+)
+
+func TestAccountCache(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	cache := newAccountCache(func(slug string) (*models.AccountMembership, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return &models.AccountMembership{ID: "id-" + slug, Slug: slug}, nil
+	})
+
+	// simulate several resources sharing the same slug concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			account, err := cache.get("team")
+			if err != nil {
+				t.Errorf("err: %s", err)
+				return
+			}
+			if account.ID != "id-team" {
+				t.Errorf("unexpected account ID: %s", account.ID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected account to be fetched once, got %d", calls)
+	}
+
+	if _, err := cache.get("other"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a new slug to be fetched, got %d fetches", calls)
+	}
+}
@@ -1,30 +1,72 @@
 package netlify
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 
 	"github.com/go-openapi/runtime"
 	openapiClient "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
 	"github.com/netlify/open-api/v2/go/porcelain"
 )
 
 type Config struct {
-	Token   string
-	BaseURL string
+	Token            string
+	BaseURL          string
+	WarnOnMissingDNS bool
+	PageSize         int
+	ReadAccountTypes bool
+
+	ReadLastDeployStates bool
+	ReadBuildHooks       bool
+
+	DefaultAccountSlug string
 }
 
 // Meta is the returned meta struct.
 type Meta struct {
 	Netlify  *porcelain.Netlify
 	AuthInfo runtime.ClientAuthInfoWriter
+
+	// The API operations, which are Netlify.Operations unless a test replaces
+	// them.
+	Operations operations.ClientService
+
+	// The requests that bypass the generated operations, which go through
+	// Netlify.Transport unless a test replaces them.
+	raw rawOperations
+
+	accounts *accountCache
+
+	// The rate limit reported by the most recent API response.
+	rateLimit *rateLimit
+
+	// Whether to warn about sites whose custom domain doesn't resolve.
+	warnOnMissingDns bool
+
+	// The page size of list requests.
+	perPage int32
+
+	// Whether to look up the team of each site to export its plan.
+	readAccountTypes bool
+
+	// Whether to look up the most recent deploy of each site.
+	readLastDeployStates bool
+
+	// Whether to list the build hooks of each site.
+	readBuildHooks bool
+
+	// The team to use for resources that don't set their own.
+	defaultAccountSlug string
 }
 
-// Client configures and returns a fully initialized NetlifyClient
-func (c *Config) Client() (interface{}, error) {
+// Client configures and returns a fully initialized NetlifyClient. API
+// traffic is logged to the logger carried by ctx.
+func (c *Config) Client(ctx context.Context) (interface{}, error) {
 	u, err := url.Parse(c.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing base_url: %s", err)
@@ -35,11 +77,18 @@ func (c *Config) Client() (interface{}, error) {
 		u.Scheme = "https"
 	}
 
-	// Create the OpenAPI client with our custom roundtripper.
+	// Create the OpenAPI client with our custom roundtrippers.
 	client := openapiClient.NewWithClient(
 		u.Host, u.Path, []string{u.Scheme},
 		cleanhttp.DefaultClient())
-	client.Transport = logging.NewTransport("Netlify", client.Transport)
+	limits := &rateLimit{}
+	transport := &roundTripperTransport{client, func(t http.RoundTripper) http.RoundTripper {
+		return newRedactingTransport(ctx, "Netlify", newRateLimitTransport(ctx, limits, t))
+	}}
+
+	// The swagger runtime dumps full requests when DEBUG is set in the
+	// environment, which would bypass the redaction above.
+	client.SetDebug(false)
 
 	// Setup our auth
 	authInfo := runtime.ClientAuthInfoWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
@@ -48,8 +97,45 @@ func (c *Config) Client() (interface{}, error) {
 		return nil
 	})
 
-	return &Meta{
-		Netlify:  porcelain.NewRetryable(client, strfmt.Default, porcelain.DefaultRetryAttempts),
+	meta := &Meta{
+		Netlify:  porcelain.NewRetryable(transport, strfmt.Default, porcelain.DefaultRetryAttempts),
 		AuthInfo: authInfo,
-	}, nil
+
+		rateLimit:        limits,
+		warnOnMissingDns: c.WarnOnMissingDNS,
+		perPage:          clampPerPage(c.PageSize),
+		readAccountTypes: c.ReadAccountTypes,
+
+		readLastDeployStates: c.ReadLastDeployStates,
+		readBuildHooks:       c.ReadBuildHooks,
+
+		defaultAccountSlug: c.DefaultAccountSlug,
+	}
+	meta.Operations = meta.Netlify.Operations
+	meta.raw = &transportRawOperations{transport: meta.Netlify.Transport, authInfo: authInfo}
+	meta.accounts = newAccountCache(meta.fetchAccount)
+
+	return meta, nil
+}
+
+// Wraps the roundtripper of each operation's http.Client. The retryable
+// transport of porcelain sends every operation through a client of its own,
+// so wrapping the runtime's client or Transport has no effect.
+type roundTripperTransport struct {
+	runtime.ClientTransport
+	wrap func(http.RoundTripper) http.RoundTripper
+}
+
+func (t *roundTripperTransport) Submit(op *runtime.ClientOperation) (interface{}, error) {
+	client := http.Client{}
+	if op.Client != nil {
+		client = *op.Client
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	client.Transport = t.wrap(transport)
+	op.Client = &client
+	return t.ClientTransport.Submit(op)
 }
@@ -0,0 +1,199 @@
+package netlify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceAccountCapabilities() *schema.Resource {
+	return &schema.Resource{
+		Description: "Exports the plan of a team with the usage limits and features that come with it, to create resources that depend on them with `count`. " +
+			"Features are read from the capabilities Netlify lists for the plan, and are `false` if it doesn't list them.",
+		ReadContext: dataSourceAccountCapabilitiesRead,
+		Schema: map[string]*schema.Schema{
+			"account_slug": {
+				Description: "The slug of the team. Defaults to the provider's `default_account_slug`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"type": {
+				Description: "The ID of the team's plan, e.g. `starter`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"type_name": {
+				Description: "The display name of the team's plan.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"sites_included": {
+				Description: "The number of sites included in the plan.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"sites_used": {
+				Description: "The number of sites the team has.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"seats_included": {
+				Description: "The number of team members included in the plan.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"seats_used": {
+				Description: "The number of members the team has.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"build_minutes_included": {
+				Description: "The number of build minutes included in the plan per billing period, including purchased packs, or `-1` if they are unlimited.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"build_minutes_used": {
+				Description: "The number of build minutes the team used in the current billing period.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"analytics": {
+				Description: "Whether the plan includes Netlify Analytics.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"large_media": {
+				Description: "Whether the plan includes Large Media.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceAccountCapabilitiesRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	slug := meta.accountSlug(d.Get("account_slug").(string))
+	if slug == "" {
+		return diag.Errorf("account_slug must be set when the provider has no default_account_slug")
+	}
+	account, err := meta.Account(slug)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var sites, seats models.AccountUsageCapability
+	if account.Capabilities != nil {
+		if account.Capabilities.Sites != nil {
+			sites = *account.Capabilities.Sites
+		}
+		if account.Capabilities.Collaborators != nil {
+			seats = *account.Capabilities.Collaborators
+		}
+	}
+
+	d.SetId(account.ID)
+	d.Set("account_slug", slug)
+	d.Set("type", account.Type)
+	d.Set("type_name", account.TypeName)
+	d.Set("sites_included", sites.Included)
+	d.Set("sites_used", sites.Used)
+	d.Set("seats_included", seats.Included)
+	d.Set("seats_used", seats.Used)
+
+	included, used, err := dataSourceAccountCapabilities_buildMinutes(meta, account.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("build_minutes_included", included)
+	d.Set("build_minutes_used", used)
+
+	features, err := dataSourceAccountCapabilities_features(meta, account.Type)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("analytics", accountTypeHasCapability(features, "analytics"))
+	d.Set("large_media", accountTypeHasCapability(features, "large_media"))
+
+	return nil
+}
+
+// Returns the build minutes included in the team's plan, -1 if they are
+// unlimited, and used in the current billing period.
+func dataSourceAccountCapabilities_buildMinutes(meta *Meta, accountID string) (int, int, error) {
+	params := operations.NewGetAccountBuildStatusParams()
+	params.AccountID = accountID
+	resp, err := meta.Operations.GetAccountBuildStatus(params, meta.AuthInfo)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(resp.Payload) == 0 || resp.Payload[0].Minutes == nil {
+		return 0, 0, nil
+	}
+	minutes := resp.Payload[0].Minutes
+	included := minutes.IncludedMinutesWithPacks
+	if included == "" {
+		included = minutes.IncludedMinutes
+	}
+	switch {
+	case included == "":
+		return 0, int(minutes.Current), nil
+	case strings.EqualFold(included, "unlimited"):
+		return -1, int(minutes.Current), nil
+	}
+
+	n, err := strconv.Atoi(included)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error reading the build minutes included in the plan of team %s: %s", accountID, err)
+	}
+	return n, int(minutes.Current), nil
+}
+
+// Returns the capabilities of the plan with the given ID, or nil if the
+// token can't see the plan.
+func dataSourceAccountCapabilities_features(meta *Meta, accountType string) (interface{}, error) {
+	resp, err := meta.Operations.ListAccountTypesForUser(operations.NewListAccountTypesForUserParams(), meta.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range resp.Payload {
+		if t.ID == accountType {
+			return t.Capabilities, nil
+		}
+	}
+	return nil, nil
+}
+
+// Reports whether a plan's capabilities include the named feature. Netlify
+// lists a feature either as a flag or as a usage limit with an `included`
+// amount.
+func accountTypeHasCapability(capabilities interface{}, name string) bool {
+	caps, ok := capabilities.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	switch v := caps[name].(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		switch included := v["included"].(type) {
+		case bool:
+			return included
+		case float64:
+			return included > 0
+		case nil:
+			return true
+		}
+	}
+	return false
+}
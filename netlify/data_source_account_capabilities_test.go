@@ -0,0 +1,163 @@
+package netlify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestDataSourceAccountCapabilitiesRead_accountSlug(t *testing.T) {
+	cases := []struct {
+		configured, expected string
+	}{
+		{configured: "", expected: "default-team"},
+		{configured: "other-team", expected: "other-team"},
+	}
+
+	for _, c := range cases {
+		var read string
+		ops := &testOperations{
+			getAccount: func(params *operations.GetAccountParams) (*operations.GetAccountOK, error) {
+				read = params.AccountID
+				return &operations.GetAccountOK{Payload: []*models.AccountMembership{{Slug: params.AccountID}}}, nil
+			},
+			getAccountBuildStatus: func(params *operations.GetAccountBuildStatusParams) (*operations.GetAccountBuildStatusOK, error) {
+				return &operations.GetAccountBuildStatusOK{}, nil
+			},
+			listAccountTypesForUser: func(params *operations.ListAccountTypesForUserParams) (*operations.ListAccountTypesForUserOK, error) {
+				return &operations.ListAccountTypesForUserOK{}, nil
+			},
+		}
+		meta := testMeta(ops)
+		meta.defaultAccountSlug = "default-team"
+
+		d := dataSourceAccountCapabilities().TestResourceData()
+		d.Set("account_slug", c.configured)
+		if diags := dataSourceAccountCapabilitiesRead(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("err: %v", diags)
+		}
+
+		if read != c.expected || d.Get("account_slug").(string) != c.expected {
+			t.Errorf("expected %q to read team %q, got %q", c.configured, c.expected, read)
+		}
+	}
+}
+
+func TestDataSourceAccountCapabilitiesRead_features(t *testing.T) {
+	ops := &testOperations{
+		getAccount: func(params *operations.GetAccountParams) (*operations.GetAccountOK, error) {
+			return &operations.GetAccountOK{Payload: []*models.AccountMembership{{ID: "team-id", Slug: params.AccountID, Type: "pro"}}}, nil
+		},
+		getAccountBuildStatus: func(params *operations.GetAccountBuildStatusParams) (*operations.GetAccountBuildStatusOK, error) {
+			if params.AccountID != "team-id" {
+				t.Errorf("expected the build status of team-id, got %q", params.AccountID)
+			}
+			return &operations.GetAccountBuildStatusOK{Payload: []*models.BuildStatus{
+				{Minutes: &models.BuildStatusMinutes{Current: 120, IncludedMinutes: "25000", IncludedMinutesWithPacks: "30000"}},
+			}}, nil
+		},
+		listAccountTypesForUser: func(params *operations.ListAccountTypesForUserParams) (*operations.ListAccountTypesForUserOK, error) {
+			return &operations.ListAccountTypesForUserOK{Payload: []*models.AccountType{
+				{ID: "starter", Capabilities: map[string]interface{}{"analytics": false}},
+				{ID: "pro", Capabilities: map[string]interface{}{
+					"analytics":   true,
+					"large_media": map[string]interface{}{"included": float64(0)},
+				}},
+			}}, nil
+		},
+	}
+
+	d := dataSourceAccountCapabilities().TestResourceData()
+	d.Set("account_slug", "team")
+	if diags := dataSourceAccountCapabilitiesRead(context.Background(), d, testMeta(ops)); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	if d.Get("build_minutes_included").(int) != 30000 || d.Get("build_minutes_used").(int) != 120 {
+		t.Errorf("expected 120 of 30000 build minutes, got %v of %v", d.Get("build_minutes_used"), d.Get("build_minutes_included"))
+	}
+	if !d.Get("analytics").(bool) || d.Get("large_media").(bool) {
+		t.Errorf("expected the pro plan's features, got analytics %v and large_media %v", d.Get("analytics"), d.Get("large_media"))
+	}
+}
+
+func TestDataSourceAccountCapabilities_buildMinutes(t *testing.T) {
+	cases := []struct {
+		minutes  *models.BuildStatusMinutes
+		included int
+		valid    bool
+	}{
+		{nil, 0, true},
+		{&models.BuildStatusMinutes{}, 0, true},
+		{&models.BuildStatusMinutes{IncludedMinutes: "300"}, 300, true},
+		{&models.BuildStatusMinutes{IncludedMinutes: "300", IncludedMinutesWithPacks: "800"}, 800, true},
+		{&models.BuildStatusMinutes{IncludedMinutes: "Unlimited"}, -1, true},
+		{&models.BuildStatusMinutes{IncludedMinutes: "lots"}, 0, false},
+	}
+
+	for _, c := range cases {
+		ops := &testOperations{
+			getAccountBuildStatus: func(params *operations.GetAccountBuildStatusParams) (*operations.GetAccountBuildStatusOK, error) {
+				return &operations.GetAccountBuildStatusOK{Payload: []*models.BuildStatus{{Minutes: c.minutes}}}, nil
+			},
+		}
+
+		included, _, err := dataSourceAccountCapabilities_buildMinutes(testMeta(ops), "team-id")
+		if (err == nil) != c.valid {
+			t.Errorf("%+v: expected valid=%t, got %v", c.minutes, c.valid, err)
+		}
+		if included != c.included {
+			t.Errorf("%+v: expected %d included build minutes, got %d", c.minutes, c.included, included)
+		}
+	}
+}
+
+func TestAccountTypeHasCapability(t *testing.T) {
+	cases := []struct {
+		capabilities interface{}
+		expected     bool
+	}{
+		{nil, false},
+		{map[string]interface{}{}, false},
+		{map[string]interface{}{"analytics": true}, true},
+		{map[string]interface{}{"analytics": false}, false},
+		{map[string]interface{}{"analytics": map[string]interface{}{"included": true}}, true},
+		{map[string]interface{}{"analytics": map[string]interface{}{"included": float64(1)}}, true},
+		{map[string]interface{}{"analytics": map[string]interface{}{"included": float64(0)}}, false},
+		{map[string]interface{}{"analytics": map[string]interface{}{}}, true},
+	}
+
+	for _, c := range cases {
+		if actual := accountTypeHasCapability(c.capabilities, "analytics"); actual != c.expected {
+			t.Errorf("expected %v to report %t, got %t", c.capabilities, c.expected, actual)
+		}
+	}
+}
+
+func TestAccDSAccountCapabilities(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDSAccountCapabilitiesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.netlify_account_capabilities.test", "type"),
+					resource.TestCheckResourceAttrSet("data.netlify_account_capabilities.test", "sites_used"),
+				),
+			},
+		},
+	})
+}
+
+var testAccDSAccountCapabilitiesConfig = `
+resource "netlify_site" "test" {}
+
+data "netlify_account_capabilities" "test" {
+	account_slug = netlify_site.test.account_slug
+}
+`
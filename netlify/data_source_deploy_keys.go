@@ -0,0 +1,61 @@
+package netlify
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceDeployKeys() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the deploy keys of the authenticated user, to pick the key for a site's `repo.deploy_key_id`. " +
+			"The Netlify API has no labels for deploy keys, so keys are told apart by their public key.",
+		ReadContext: dataSourceDeployKeysRead,
+		Schema: map[string]*schema.Schema{
+			"deploy_keys": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDeployKeysRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	resp, err := meta.Operations.ListDeployKeys(operations.NewListDeployKeysParams(), meta.AuthInfo)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(resp.Payload))
+	for _, key := range resp.Payload {
+		result = append(result, map[string]interface{}{
+			"id":         key.ID,
+			"public_key": key.PublicKey,
+			"created_at": key.CreatedAt,
+		})
+	}
+
+	d.SetId("deploy_keys")
+	d.Set("deploy_keys", result)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package netlify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestAccDSDeployKeys(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDSDeployKeysConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttrPair("data.netlify_deploy_keys.test", "deploy_keys.*.id", "netlify_deploy_key.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceDeployKeysRead(t *testing.T) {
+	ops := &testOperations{
+		listDeployKeys: func(params *operations.ListDeployKeysParams) (*operations.ListDeployKeysOK, error) {
+			return &operations.ListDeployKeysOK{Payload: []*models.DeployKey{
+				{ID: "first", PublicKey: "ssh-rsa first", CreatedAt: "2020-01-01T00:00:00Z"},
+				{ID: "second", PublicKey: "ssh-rsa second"},
+			}}, nil
+		},
+	}
+
+	d := dataSourceDeployKeys().TestResourceData()
+	if diags := dataSourceDeployKeysRead(context.Background(), d, testMeta(ops)); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	if actual := d.Get("deploy_keys.#").(int); actual != 2 {
+		t.Fatalf("expected 2 deploy keys, got %d", actual)
+	}
+	if actual := d.Get("deploy_keys.0.created_at").(string); actual != "2020-01-01T00:00:00Z" {
+		t.Errorf("expected created_at to be read, got %q", actual)
+	}
+	if actual := d.Get("deploy_keys.1.public_key").(string); actual != "ssh-rsa second" {
+		t.Errorf("expected public_key to be read, got %q", actual)
+	}
+}
+
+var testAccDSDeployKeysConfig = `
+resource "netlify_deploy_key" "test" {}
+
+data "netlify_deploy_keys" "test" {
+	depends_on = [netlify_deploy_key.test]
+}
+`
@@ -0,0 +1,113 @@
+package netlify
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceDeploys() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the most recent deploys of a site, newest first.",
+		ReadContext: dataSourceDeploysRead,
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Description: "The ID of the site.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"branch": {
+				Description: "If provided, only lists deploys of this branch.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"limit": {
+				Description: "The maximum number of deploys to list, or 0 to list every deploy.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+			},
+			"deploys": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"branch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"commit_ref": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"deploy_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDeploysRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	siteID := d.Get("site_id").(string)
+	branch := d.Get("branch").(string)
+
+	// the API can't filter by branch, so filter while paging instead
+	var keep func(*models.Deploy) bool
+	if branch != "" {
+		keep = func(deploy *models.Deploy) bool {
+			return deploy.Branch == branch
+		}
+	}
+
+	deploys, err := paginateFiltered(meta.perPage, d.Get("limit").(int), keep, func(page int32, perPage int32) ([]*models.Deploy, error) {
+		params := operations.NewListSiteDeploysParams()
+		params.SiteID = siteID
+		params.Page = &page
+		params.PerPage = &perPage
+		resp, err := meta.Operations.ListSiteDeploys(params, meta.AuthInfo)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Payload, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(deploys))
+	for _, deploy := range deploys {
+		result = append(result, map[string]interface{}{
+			"id":         deploy.ID,
+			"state":      deploy.State,
+			"branch":     deploy.Branch,
+			"commit_ref": deploy.CommitRef,
+			"created_at": deploy.CreatedAt,
+			"deploy_url": deploy.DeployURL,
+		})
+	}
+
+	d.SetId(siteID)
+	d.Set("deploys", result)
+
+	return nil
+}
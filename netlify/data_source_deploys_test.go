@@ -0,0 +1,34 @@
+package netlify
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDSDeploys(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDSDeploysConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.netlify_deploys.test", "id", "netlify_site.test", "id"),
+					resource.TestCheckResourceAttr("data.netlify_deploys.test", "deploys.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccDSDeploysConfig = `
+resource "netlify_site" "test" {}
+
+data "netlify_deploys" "test" {
+	site_id = netlify_site.test.id
+	branch = "main"
+	limit = 5
+}
+`
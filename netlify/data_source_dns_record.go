@@ -0,0 +1,91 @@
+package netlify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceDnsRecord() *schema.Resource {
+	return &schema.Resource{
+		Description: "Looks up a single record of a DNS zone by hostname and type.",
+		ReadContext: dataSourceDnsRecordRead,
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Description: "The ID of the DNS zone.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"hostname": {
+				Description: "The hostname of the record, either fully qualified or relative to the zone (`@` for the zone apex).",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"type": {
+				Description: "The type of the record, e.g. `A` or `MX`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"value": {
+				Description: "The value of the record. Required when several records share the hostname and type, e.g. multiple `MX` records.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"ttl": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"priority": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDnsRecordRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	zoneID := d.Get("zone_id").(string)
+
+	zone, err := resourceDnsRecord_zoneName(meta, zoneID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	params := operations.NewGetDNSRecordsParams()
+	params.ZoneID = zoneID
+	resp, err := meta.Operations.GetDNSRecords(params, meta.AuthInfo)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	record, err := dataSourceDnsRecord_find(resp.Payload, zone, d.Get("hostname").(string), d.Get("type").(string), d.Get("value").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(record.ID)
+	d.Set("value", record.Value)
+	d.Set("ttl", record.TTL)
+	d.Set("priority", record.Priority)
+
+	return nil
+}
+
+// Finds the one record matching hostname and type (and value, if given).
+func dataSourceDnsRecord_find(records []*models.DNSRecord, zone string, hostname string, recordType string, value string) (*models.DNSRecord, error) {
+	matches := resourceDnsRecord_matching(records, zone, hostname, recordType, value)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("No %s record found for %s", recordType, normalizeDnsHostname(hostname, zone))
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("Found %d %s records for %s; set value to select one of them", len(matches), recordType, normalizeDnsHostname(hostname, zone))
+	}
+}
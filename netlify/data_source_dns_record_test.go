@@ -0,0 +1,75 @@
+package netlify
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/netlify/open-api/v2/go/models"
+)
+
+func TestAccDSDnsRecord(t *testing.T) {
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDSDnsRecordConfig, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.netlify_dns_record.test", "id", "netlify_dns_record.test", "id"),
+					resource.TestCheckResourceAttr("data.netlify_dns_record.test", "value", "10.0.0.1"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceDnsRecord_find(t *testing.T) {
+	records := []*models.DNSRecord{
+		{ID: "a", Hostname: "www.example.com", Type: "A", Value: "10.0.0.1"},
+		{ID: "mx1", Hostname: "example.com", Type: "MX", Value: "mx1.example.net"},
+		{ID: "mx2", Hostname: "example.com", Type: "MX", Value: "mx2.example.net"},
+	}
+
+	if record, err := dataSourceDnsRecord_find(records, "example.com", "www", "a", ""); err != nil || record.ID != "a" {
+		t.Fatalf("expected record a, got %v, %v", record, err)
+	}
+
+	if _, err := dataSourceDnsRecord_find(records, "example.com", "@", "MX", ""); err == nil {
+		t.Fatal("expected an error for multiple matches")
+	}
+
+	if record, err := dataSourceDnsRecord_find(records, "example.com", "@", "MX", "mx2.example.net"); err != nil || record.ID != "mx2" {
+		t.Fatalf("expected record mx2, got %v, %v", record, err)
+	}
+
+	if _, err := dataSourceDnsRecord_find(records, "example.com", "mail", "A", ""); err == nil {
+		t.Fatal("expected an error for no matches")
+	}
+}
+
+var testAccDSDnsRecordConfig = `
+resource "netlify_site" "test" {}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%s"
+}
+
+resource "netlify_dns_record" "test" {
+	zone_id = netlify_dns_zone.test.id
+	hostname = "www"
+	type = "A"
+	value = "10.0.0.1"
+}
+
+data "netlify_dns_record" "test" {
+	zone_id = netlify_dns_zone.test.id
+	hostname = "www"
+	type = "A"
+	depends_on = [netlify_dns_record.test]
+}
+`
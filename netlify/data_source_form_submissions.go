@@ -0,0 +1,122 @@
+package netlify
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceFormSubmissions() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the submissions of a form, e.g. to find the ones to erase with `netlify_form_submission`.",
+		ReadContext: dataSourceFormSubmissionsRead,
+		Schema: map[string]*schema.Schema{
+			"form_id": {
+				Description: "The ID of the form.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"submissions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"name": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"data": {
+							Description: "The submitted fields. Values that aren't strings are JSON-encoded.",
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Sensitive:   true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFormSubmissionsRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	formID := d.Get("form_id").(string)
+
+	submissions, err := paginate(meta.perPage, func(page int32, perPage int32) ([]*models.Submission, error) {
+		params := operations.NewListFormSubmissionsParams()
+		params.FormID = formID
+		params.Page = &page
+		params.PerPage = &perPage
+		resp, err := meta.Operations.ListFormSubmissions(params, meta.AuthInfo)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Payload, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(submissions))
+	for _, submission := range submissions {
+		result = append(result, map[string]interface{}{
+			"id":         submission.ID,
+			"number":     submission.Number,
+			"created_at": submission.CreatedAt,
+			"email":      submission.Email,
+			"name":       submission.Name,
+			"data":       flattenSubmissionData(submission.Data),
+		})
+	}
+
+	d.SetId(formID)
+	d.Set("submissions", result)
+
+	return nil
+}
+
+// Converts the submitted fields to a string map, JSON-encoding values that
+// aren't strings.
+func flattenSubmissionData(data interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return result
+	}
+
+	for k, v := range fields {
+		if s, ok := v.(string); ok {
+			result[k] = s
+			continue
+		}
+		if b, err := json.Marshal(v); err == nil {
+			result[k] = string(b)
+		}
+	}
+	return result
+}
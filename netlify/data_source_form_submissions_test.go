@@ -0,0 +1,59 @@
+package netlify
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDSFormSubmissions(t *testing.T) {
+	formID := os.Getenv("NETLIFY_TEST_FORM_ID")
+	if formID == "" {
+		t.Skip("NETLIFY_TEST_FORM_ID must be set to test form submissions")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDSFormSubmissionsConfig, formID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.netlify_form_submissions.test", "id", formID),
+					resource.TestCheckResourceAttrSet("data.netlify_form_submissions.test", "submissions.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenSubmissionData(t *testing.T) {
+	data := flattenSubmissionData(map[string]interface{}{
+		"email":   "jane@example.com",
+		"consent": true,
+		"tags":    []interface{}{"a", "b"},
+	})
+
+	expected := map[string]string{
+		"email":   "jane@example.com",
+		"consent": "true",
+		"tags":    `["a","b"]`,
+	}
+	for k, v := range expected {
+		if data[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, data[k])
+		}
+	}
+
+	if len(flattenSubmissionData(nil)) != 0 {
+		t.Error("expected no fields for empty data")
+	}
+}
+
+var testAccDSFormSubmissionsConfig = `
+data "netlify_form_submissions" "test" {
+	form_id = "%s"
+}
+`
@@ -0,0 +1,61 @@
+package netlify
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceRateLimit() *schema.Resource {
+	return &schema.Resource{
+		Description: "Exports the API rate limit of the provider's token as Netlify reports it at read time. " +
+			"The limit reported by every response is also logged at DEBUG level, to follow the remaining quota during an apply.",
+		ReadContext: dataSourceRateLimitRead,
+		Schema: map[string]*schema.Schema{
+			"reported": {
+				Description: "Whether Netlify reported a rate limit. If not, the other attributes are unset.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"limit": {
+				Description: "The number of requests allowed in the current window.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"remaining": {
+				Description: "The number of requests left in the current window.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"reset_at": {
+				Description: "When the current window ends and the quota is reset, in RFC 3339 format.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceRateLimitRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+
+	// Fetch the current user so the values are current rather than left over
+	// from whichever request came last
+	if err := validateToken(meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	limit, remaining, reset, ok := meta.rateLimit.get()
+	d.SetId("rate_limit")
+	d.Set("reported", ok)
+	d.Set("limit", limit)
+	d.Set("remaining", remaining)
+	d.Set("reset_at", "")
+	if !reset.IsZero() {
+		d.Set("reset_at", reset.Format(time.RFC3339))
+	}
+
+	return nil
+}
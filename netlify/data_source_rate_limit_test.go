@@ -0,0 +1,76 @@
+package netlify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDSRateLimit(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "netlify_rate_limit" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.netlify_rate_limit.test", "reported"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceRateLimitRead(t *testing.T) {
+	reportLimit := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if reportLimit {
+			w.Header().Set("X-RateLimit-Limit", "500")
+			w.Header().Set("X-RateLimit-Remaining", "498")
+			w.Header().Set("X-RateLimit-Reset", "1700000000")
+		}
+		w.Write([]byte(`{"id":"user"}`))
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := dataSourceRateLimit().TestResourceData()
+	if diags := dataSourceRateLimitRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	if !d.Get("reported").(bool) || d.Get("limit").(int) != 500 || d.Get("remaining").(int) != 498 {
+		t.Errorf("expected the rate limit headers to be read, got %v of %v", d.Get("remaining"), d.Get("limit"))
+	}
+	if actual := d.Get("reset_at").(string); actual != "2023-11-14T22:13:20Z" {
+		t.Errorf("expected the reset time to be read, got %q", actual)
+	}
+}
+
+func TestRateLimit_record(t *testing.T) {
+	limits := &rateLimit{}
+	if limits.record(http.Header{}) {
+		t.Errorf("expected a response without rate limit headers to be ignored")
+	}
+	if _, _, _, ok := limits.get(); ok {
+		t.Errorf("expected no rate limit to be known yet")
+	}
+
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", "10")
+	if !limits.record(h) {
+		t.Fatalf("expected the remaining quota to be recorded")
+	}
+	limit, remaining, reset, ok := limits.get()
+	if !ok || limit != 0 || remaining != 10 || !reset.IsZero() {
+		t.Errorf("expected only the remaining quota, got %d of %d until %s", remaining, limit, reset)
+	}
+}
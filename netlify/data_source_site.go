@@ -2,8 +2,6 @@ package netlify
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -36,6 +34,11 @@ func dataSourceSite() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"netlify_dns_target": {
+				Description: "The hostname to point `CNAME` or `ALIAS` records for the site's custom domains at, i.e. the site's Netlify subdomain.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"account_slug": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -44,6 +47,24 @@ func dataSourceSite() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"scheduled_functions": {
+				Description: "The scheduled functions of the site's published deploy. The Netlify API does not report unscheduled functions or function routes.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"schedule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"repo": {
 				Type:     schema.TypeList,
 				MaxItems: 1,
@@ -94,7 +115,7 @@ func dataSourceSiteRead(ctx context.Context, d *schema.ResourceData, metaRaw int
 	if id, ok := d.GetOk("site_id"); ok {
 		params := operations.NewGetSiteParams()
 		params.SiteID = id.(string)
-		resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetSite(params, meta.AuthInfo)
 		if err != nil {
 			// If it is a 404 it was removed remotely
 			if v, ok := err.(*operations.GetSiteDefault); ok && v.Code() == 404 {
@@ -104,19 +125,24 @@ func dataSourceSiteRead(ctx context.Context, d *schema.ResourceData, metaRaw int
 			return diag.FromErr(err)
 		}
 		site = resp.Payload
-		bs, _ := json.Marshal(site)
-		fmt.Print(string(bs))
 		// otherwise, query all sites and look for ones that match
 	} else {
-		params := operations.NewListSitesParams()
 		name := d.Get("name").(string)
-		params.Name = &name
-		resp, err := meta.Netlify.Operations.ListSites(params, meta.AuthInfo)
+		sites, err := paginate(meta.perPage, func(page int32, perPage int32) ([]*models.Site, error) {
+			params := operations.NewListSitesParams()
+			params.Name = &name
+			params.Page = &page
+			params.PerPage = &perPage
+			resp, err := meta.Operations.ListSites(params, meta.AuthInfo)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Payload, nil
+		})
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		// name match should be first one. if it's not, then name is not specific enough.
-		sites := resp.Payload
+		// the name filter may return several sites, so look for an exact match.
 		has_match := false
 		for _, siteI := range sites {
 			if siteI.Name == name {
@@ -133,8 +159,14 @@ func dataSourceSiteRead(ctx context.Context, d *schema.ResourceData, metaRaw int
 
 	// at this point, the correct site has been gotten, if it exists. so
 	// we populate it exactly as we do for the site resource.
+	target, err := getSiteDefaultDomain(meta, site.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(site.ID)
 	d.Set("site_id", site.ID)
+	d.Set("netlify_dns_target", target)
 	d.Set("name", site.Name)
 	d.Set("custom_domain", site.CustomDomain)
 	d.Set("deploy_url", site.DeployURL)
@@ -142,6 +174,17 @@ func dataSourceSiteRead(ctx context.Context, d *schema.ResourceData, metaRaw int
 	d.Set("account_name", site.AccountName)
 	d.Set("repo", nil)
 
+	functions := []interface{}{}
+	if site.PublishedDeploy != nil {
+		for _, f := range site.PublishedDeploy.FunctionSchedules {
+			functions = append(functions, map[string]interface{}{
+				"name":     f.Name,
+				"schedule": f.Cron,
+			})
+		}
+	}
+	d.Set("scheduled_functions", functions)
+
 	if site.BuildSettings != nil && site.BuildSettings.RepoPath != "" {
 		d.Set("repo", []interface{}{
 			map[string]interface{}{
@@ -0,0 +1,51 @@
+package netlify
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceSiteAssetPublicSignature() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches a signed URL for a private site asset. Uploading an asset takes two steps outside of Terraform: creating the asset returns a signed upload form, and the file is then posted to that form. " +
+			"The URL is re-fetched on every read. The plugin SDK the provider is built on has no ephemeral values, so like every data source result the URL is written to state; it is marked sensitive, and the state has to be protected accordingly.",
+		ReadContext: dataSourceSiteAssetPublicSignatureRead,
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Description: "The ID of the site the asset belongs to.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"asset_id": {
+				Description: "The ID of the asset.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"url": {
+				Description: "The signed URL granting access to the asset.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func dataSourceSiteAssetPublicSignatureRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	params := operations.NewGetSiteAssetPublicSignatureParams()
+	params.SiteID = d.Get("site_id").(string)
+	params.AssetID = d.Get("asset_id").(string)
+	resp, err := meta.Operations.GetSiteAssetPublicSignature(params, meta.AuthInfo)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(params.AssetID)
+	d.Set("url", resp.Payload.URL)
+
+	return nil
+}
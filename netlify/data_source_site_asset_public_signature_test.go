@@ -0,0 +1,34 @@
+package netlify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestDataSourceSiteAssetPublicSignatureRead(t *testing.T) {
+	ops := &testOperations{
+		getSiteAssetPublicSignature: func(params *operations.GetSiteAssetPublicSignatureParams) (*operations.GetSiteAssetPublicSignatureOK, error) {
+			if params.SiteID != "site" || params.AssetID != "asset" {
+				t.Errorf("expected the signature of asset on site, got %s on %s", params.AssetID, params.SiteID)
+			}
+			return &operations.GetSiteAssetPublicSignatureOK{Payload: &models.AssetPublicSignature{URL: "https://assets.netlify.com/asset?signature=secret"}}, nil
+		},
+	}
+
+	d := dataSourceSiteAssetPublicSignature().TestResourceData()
+	d.Set("site_id", "site")
+	d.Set("asset_id", "asset")
+	if diags := dataSourceSiteAssetPublicSignatureRead(context.Background(), d, testMeta(ops)); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	if d.Id() != "asset" || d.Get("url").(string) != "https://assets.netlify.com/asset?signature=secret" {
+		t.Errorf("expected the signed URL of asset, got %q for %q", d.Get("url"), d.Id())
+	}
+	if !dataSourceSiteAssetPublicSignature().Schema["url"].Sensitive {
+		t.Errorf("expected the signed URL to be sensitive")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -27,6 +28,8 @@ func TestAccDSSite(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckSiteExists(resourceName, &site),
 					testAccCheckSiteMatches("data.netlify_site.test", site),
+					resource.TestCheckResourceAttr("data.netlify_site.test", "scheduled_functions.#", "0"),
+					resource.TestMatchResourceAttr("data.netlify_site.test", "netlify_dns_target", regexp.MustCompile(`\.netlify\.app$`)),
 				),
 			},
 		},
@@ -48,7 +51,7 @@ func testAccCheckSiteMatches(n string, other models.Site) resource.TestCheckFunc
 		// get the site on the backend
 		params := operations.NewGetSiteParams()
 		params.SiteID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetSite(params, meta.AuthInfo)
 		if err != nil {
 			return err
 		}
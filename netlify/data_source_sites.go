@@ -0,0 +1,106 @@
+package netlify
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceSites() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the sites the token has access to, optionally limited to a single team. Combined with `terraform import` this can be used to bring every site in an account under management.",
+		ReadContext: dataSourceSitesRead,
+		Schema: map[string]*schema.Schema{
+			"account_slug": {
+				Description: "If provided, only lists the sites of this team. Defaults to the provider's `default_account_slug`; without either, lists every site the token has access to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"sites": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"custom_domain": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"account_slug": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSitesRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	slug := meta.accountSlug(d.Get("account_slug").(string))
+
+	sites, err := listSites(meta, slug)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(sites))
+	for _, site := range sites {
+		result = append(result, map[string]interface{}{
+			"id":            site.ID,
+			"name":          site.Name,
+			"custom_domain": site.CustomDomain,
+			"account_slug":  site.AccountSlug,
+		})
+	}
+
+	if slug != "" {
+		d.SetId(slug)
+	} else {
+		d.SetId("sites")
+	}
+	d.Set("account_slug", slug)
+	d.Set("sites", result)
+
+	return nil
+}
+
+// Lists all sites the token has access to, or only those of the team with the
+// given slug.
+func listSites(meta *Meta, slug string) ([]*models.Site, error) {
+	return paginate(meta.perPage, func(page int32, perPage int32) ([]*models.Site, error) {
+		if slug != "" {
+			params := operations.NewListSitesForAccountParams()
+			params.AccountSlug = slug
+			params.Page = &page
+			params.PerPage = &perPage
+			resp, err := meta.Operations.ListSitesForAccount(params, meta.AuthInfo)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Payload, nil
+		}
+
+		params := operations.NewListSitesParams()
+		params.Page = &page
+		params.PerPage = &perPage
+		resp, err := meta.Operations.ListSites(params, meta.AuthInfo)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Payload, nil
+	})
+}
@@ -0,0 +1,89 @@
+package netlify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestAccDSSites(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDSSitesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSitesContains("data.netlify_sites.test", "netlify_site.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceSitesRead_accountSlug(t *testing.T) {
+	cases := []struct {
+		defaultSlug, configured, expected string
+	}{
+		{defaultSlug: "", configured: "", expected: ""},
+		{defaultSlug: "default-team", configured: "", expected: "default-team"},
+		{defaultSlug: "default-team", configured: "other-team", expected: "other-team"},
+	}
+
+	for _, c := range cases {
+		listed := "all"
+		ops := &testOperations{
+			listSites: func(params *operations.ListSitesParams) (*operations.ListSitesOK, error) {
+				return &operations.ListSitesOK{Payload: []*models.Site{}}, nil
+			},
+			listSitesForAccount: func(params *operations.ListSitesForAccountParams) (*operations.ListSitesForAccountOK, error) {
+				listed = params.AccountSlug
+				return &operations.ListSitesForAccountOK{Payload: []*models.Site{}}, nil
+			},
+		}
+		meta := testMeta(ops)
+		meta.defaultAccountSlug = c.defaultSlug
+
+		d := dataSourceSites().TestResourceData()
+		d.Set("account_slug", c.configured)
+		if diags := dataSourceSitesRead(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("err: %v", diags)
+		}
+
+		expected := c.expected
+		if expected == "" {
+			expected = "all"
+		}
+		if listed != expected || d.Get("account_slug").(string) != c.expected {
+			t.Errorf("expected %+v to list the sites of %q, got %q", c, expected, listed)
+		}
+	}
+}
+
+func testAccCheckSitesContains(n string, site string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[site]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", site)
+		}
+
+		return resource.TestCheckTypeSetElemNestedAttrs(n, "sites.*", map[string]string{
+			"id": rs.Primary.ID,
+		})(s)
+	}
+}
+
+var testAccDSSitesConfig = `
+resource "netlify_site" "test" {}
+
+data "netlify_sites" "test" {
+	account_slug = netlify_site.test.account_slug
+	depends_on = [netlify_site.test]
+}
+`
@@ -0,0 +1,112 @@
+package netlify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func dataSourceSplitTest() *schema.Resource {
+	return &schema.Resource{
+		Description: "Looks up a split test of a site by name, e.g. one created in the Netlify UI.",
+		ReadContext: dataSourceSplitTestRead,
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Description: "The ID of the site.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "The name of the split test.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"active": {
+				Description: "Whether the split test is currently running.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"branches": {
+				Description: "The branches traffic is split between.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"branch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"percentage": {
+							Description: "The percentage of traffic served from the branch.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSplitTestRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	params := operations.NewGetSplitTestsParams()
+	params.SiteID = d.Get("site_id").(string)
+	resp, err := meta.Operations.GetSplitTests(params, meta.AuthInfo)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	splitTest, err := dataSourceSplitTest_find(resp.Payload, d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(splitTest.ID)
+	d.Set("active", splitTest.Active)
+	d.Set("branches", dataSourceSplitTest_branches(splitTest.Branches))
+
+	return nil
+}
+
+// Finds the one split test with the given name.
+func dataSourceSplitTest_find(splitTests []*models.SplitTest, name string) (*models.SplitTest, error) {
+	var matches []*models.SplitTest
+	for _, splitTest := range splitTests {
+		if splitTest.Name == name {
+			matches = append(matches, splitTest)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("No split test named %q found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("Found %d split tests named %q; rename them in the Netlify UI so the name is unique", len(matches), name)
+	}
+}
+
+// Flattens the untyped branches of a split test, which the API returns as
+// objects with a branch and a percentage.
+func dataSourceSplitTest_branches(raw []interface{}) []interface{} {
+	branches := make([]interface{}, 0, len(raw))
+	for _, v := range raw {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		branch, _ := m["branch"].(string)
+		percentage, _ := m["percentage"].(float64)
+		branches = append(branches, map[string]interface{}{
+			"branch":     branch,
+			"percentage": int(percentage),
+		})
+	}
+	return branches
+}
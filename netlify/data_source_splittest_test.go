@@ -0,0 +1,75 @@
+package netlify
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/netlify/open-api/v2/go/models"
+)
+
+// Split tests need deployed branches, so this reads one that already exists.
+func TestAccDSSplitTest(t *testing.T) {
+	siteID := os.Getenv("NETLIFY_TEST_SPLIT_TEST_SITE_ID")
+	name := os.Getenv("NETLIFY_TEST_SPLIT_TEST_NAME")
+	if siteID == "" || name == "" {
+		t.Skip("NETLIFY_TEST_SPLIT_TEST_SITE_ID and NETLIFY_TEST_SPLIT_TEST_NAME must be set to test split tests")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDSSplitTestConfig, siteID, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.netlify_split_test.test", "id"),
+					resource.TestCheckResourceAttrSet("data.netlify_split_test.test", "branches.0.branch"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceSplitTest_find(t *testing.T) {
+	splitTests := []*models.SplitTest{
+		{ID: "a", Name: "checkout"},
+		{ID: "b", Name: "pricing"},
+		{ID: "c", Name: "pricing"},
+	}
+
+	if splitTest, err := dataSourceSplitTest_find(splitTests, "checkout"); err != nil || splitTest.ID != "a" {
+		t.Fatalf("expected split test a, got %v, %v", splitTest, err)
+	}
+
+	if _, err := dataSourceSplitTest_find(splitTests, "pricing"); err == nil {
+		t.Fatal("expected an error for multiple matches")
+	}
+
+	if _, err := dataSourceSplitTest_find(splitTests, "missing"); err == nil {
+		t.Fatal("expected an error for no matches")
+	}
+}
+
+func TestDataSourceSplitTest_branches(t *testing.T) {
+	actual := dataSourceSplitTest_branches([]interface{}{
+		map[string]interface{}{"branch": "main", "percentage": float64(80)},
+		map[string]interface{}{"branch": "redesign", "percentage": float64(20)},
+	})
+	expected := []interface{}{
+		map[string]interface{}{"branch": "main", "percentage": 80},
+		map[string]interface{}{"branch": "redesign", "percentage": 20},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+var testAccDSSplitTestConfig = `
+data "netlify_split_test" "test" {
+	site_id = "%s"
+	name = "%s"
+}
+`
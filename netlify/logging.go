@@ -0,0 +1,235 @@
+package netlify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sirupsen/logrus"
+)
+
+// JSON keys whose values are never written to the debug log, since they
+// carry environment variable values, hook URLs and other secrets, or the
+// personal data of form submissions.
+var sensitiveLogKeys = map[string]bool{
+	"value":           true,
+	"url":             true,
+	"data":            true,
+	"password":        true,
+	"certificate":     true,
+	"ca_certificates": true,
+	"deploy_hook":     true,
+	"access_token":    true,
+	"env":             true,
+
+	"email":        true,
+	"name":         true,
+	"body":         true,
+	"human_fields": true,
+}
+
+var authorizationHeader = regexp.MustCompile(`(?mi)^(Authorization:\s*).*$`)
+
+// Query parameters whose values are never written to the debug log.
+// ProvisionSiteTLSCertificate sends the certificate and its private key in
+// the query rather than the body.
+var sensitiveQueryParams = regexp.MustCompile(`([?&](?:certificate|key|ca_certificates)=)[^&\s#]*`)
+
+const redacted = "[REDACTED]"
+
+// Bodies larger than this are left out of the debug log.
+const maxLoggedBodySize = 64 << 10
+
+// Logs API requests and responses at DEBUG level through tflog, with the
+// token and secret values redacted. Set TF_LOG (or TF_LOG_PROVIDER) to DEBUG
+// to see them. Only JSON bodies are logged, so the files uploaded by a deploy
+// aren't buffered or written to the log.
+type redactingTransport struct {
+	// The context the provider was configured with. The API client doesn't
+	// pass the SDK's contexts through to requests, so this is what carries
+	// the provider's logger.
+	ctx       context.Context
+	name      string
+	transport http.RoundTripper
+
+	// Whether DEBUG logs are enabled, so requests are only dumped if the
+	// dump is going to be written.
+	debug bool
+}
+
+func newRedactingTransport(ctx context.Context, name string, t http.RoundTripper) http.RoundTripper {
+	return &redactingTransport{ctx, name, t, debugLogEnabled()}
+}
+
+// tflog has no way to ask for the level of the provider's logger, so this
+// checks the variables Terraform sets it from, which the provider inherits.
+// The most specific one that is set wins.
+func debugLogEnabled() bool {
+	for _, env := range []string{"TF_LOG_PROVIDER_NETLIFY", "TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := strings.ToUpper(os.Getenv(env)); level != "" {
+			return level == "DEBUG" || level == "TRACE" || level == "JSON"
+		}
+	}
+	return false
+}
+
+// Whether to include a body with the given headers and length in the log.
+// The length is -1 if it is unknown, e.g. for chunked JSON responses.
+func logBody(header http.Header, length int64) bool {
+	return strings.Contains(header.Get("Content-Type"), "json") && length <= maxLoggedBodySize
+}
+
+func (t *redactingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.debug {
+		return t.transport.RoundTrip(req)
+	}
+
+	fields := map[string]interface{}{
+		"http_method": req.Method,
+		"http_url":    redactQuery(req.URL.String()),
+	}
+
+	reqData, err := httputil.DumpRequestOut(req, logBody(req.Header, req.ContentLength))
+	if err == nil {
+		tflog.Debug(t.ctx, fmt.Sprintf("%s API Request Details:\n%s", t.name, redactLogMessage(reqData)), fields)
+	} else {
+		tflog.Error(t.ctx, fmt.Sprintf("%s API Request error: %#v", t.name, err), fields)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	fields["http_status_code"] = resp.StatusCode
+	// Netlify support can look requests up by this ID
+	if id := resp.Header.Get("X-Nf-Request-Id"); id != "" {
+		fields["netlify_request_id"] = id
+	}
+
+	respData, err := httputil.DumpResponse(resp, logBody(resp.Header, resp.ContentLength))
+	if err == nil {
+		tflog.Debug(t.ctx, fmt.Sprintf("%s API Response Details:\n%s", t.name, redactLogMessage(respData)), fields)
+	} else {
+		tflog.Error(t.ctx, fmt.Sprintf("%s API Response error: %#v", t.name, err), fields)
+	}
+
+	return resp, nil
+}
+
+// Redacts the values of sensitive query parameters in a URL or request line.
+func redactQuery(s string) string {
+	return sensitiveQueryParams.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// Redacts the authorization header, sensitive query parameters and any
+// sensitive values in the JSON body of a dumped HTTP message, pretty-printing
+// the JSON along the way. A body that doesn't parse as a whole, e.g. because
+// it is chunked, is redacted line by line instead.
+func redactLogMessage(b []byte) string {
+	b = authorizationHeader.ReplaceAll(b, []byte("${1}"+redacted))
+	b = sensitiveQueryParams.ReplaceAll(b, []byte("${1}"+redacted))
+
+	head, body, ok := strings.Cut(string(b), "\r\n\r\n")
+	if !ok {
+		return redactJSONLines(string(b))
+	}
+	if out, ok := redactJSONText(body); ok {
+		return head + "\r\n\r\n" + out
+	}
+	return head + "\r\n\r\n" + redactJSONLines(body)
+}
+
+// Redacts each line of s that is a JSON document on its own.
+func redactJSONLines(s string) string {
+	parts := strings.Split(s, "\n")
+	for i, p := range parts {
+		if out, ok := redactJSONText(p); ok {
+			parts[i] = out
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Returns s with its sensitive values redacted, pretty-printed, and false if
+// s isn't JSON.
+func redactJSONText(s string) (string, bool) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return "", false
+	}
+
+	out, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return "", false
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, out, "", " "); err != nil {
+		return "", false
+	}
+	return pretty.String(), true
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if sensitiveLogKeys[k] {
+				v[k] = redacted
+			} else {
+				v[k] = redactJSON(val)
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactJSON(val)
+		}
+	}
+	return v
+}
+
+// Returns a logger for the porcelain client, which logs the progress of
+// deploys through logrus. It writes nothing itself and hands every entry to
+// tflog instead, so deploys log to the provider's log like API traffic does.
+func newPorcelainLogger(ctx context.Context) *logrus.Entry {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(&tflogHook{ctx: ctx})
+	return logrus.NewEntry(logger)
+}
+
+// Forwards logrus entries to tflog at the matching level.
+type tflogHook struct {
+	ctx context.Context
+}
+
+func (h *tflogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *tflogHook) Fire(entry *logrus.Entry) error {
+	fields := map[string]interface{}(entry.Data)
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		tflog.Error(h.ctx, entry.Message, fields)
+	case logrus.WarnLevel:
+		tflog.Warn(h.ctx, entry.Message, fields)
+	case logrus.InfoLevel:
+		tflog.Info(h.ctx, entry.Message, fields)
+	case logrus.DebugLevel:
+		tflog.Debug(h.ctx, entry.Message, fields)
+	default:
+		tflog.Trace(h.ctx, entry.Message, fields)
+	}
+	return nil
+}
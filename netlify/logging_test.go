@@ -0,0 +1,223 @@
+package netlify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestRedactLogMessage(t *testing.T) {
+	msg := "POST /api/v1/accounts/test/env HTTP/1.1\r\n" +
+		"Authorization: Bearer secret-token\r\n" +
+		"\r\n" +
+		`[{"key":"API_KEY","values":[{"context":"all","value":"secret-value"}]}]`
+
+	out := redactLogMessage([]byte(msg))
+	for _, secret := range []string{"secret-token", "secret-value"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("log message contains %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "API_KEY") {
+		t.Fatalf("log message lost non-sensitive values:\n%s", out)
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	url := "https://api.netlify.com/api/v1/sites/site/ssl?ca_certificates=ca-secret&certificate=cert-secret&key=key-secret&site_id=site"
+
+	for _, out := range []string{
+		redactQuery(url),
+		redactLogMessage([]byte("POST " + url + " HTTP/1.1\r\nHost: api.netlify.com\r\n\r\n")),
+	} {
+		for _, secret := range []string{"ca-secret", "cert-secret", "key-secret"} {
+			if strings.Contains(out, secret) {
+				t.Fatalf("log message contains %q:\n%s", secret, out)
+			}
+		}
+		if !strings.Contains(out, "site_id=site") {
+			t.Fatalf("log message lost non-sensitive query parameters:\n%s", out)
+		}
+	}
+}
+
+// Sends requests through a client configured like the provider's and returns
+// what was logged.
+func testLoggedRequests(t *testing.T, send func(meta *Meta)) string {
+	return testLoggedResponses(t, `{"id":"user"}`, send)
+}
+
+// Like testLoggedRequests, with the server answering every request with the
+// given JSON body.
+func testLoggedResponses(t *testing.T, body string, send func(meta *Meta)) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Nf-Request-Id", "request-id")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	config := Config{Token: "secret-token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(tflogtest.RootLogger(context.Background(), &out))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	send(client.(*Meta))
+	return out.String()
+}
+
+func TestRedactingTransport_client(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_NETLIFY", "")
+	t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+
+	out := testLoggedRequests(t, func(meta *Meta) {
+		if err := validateToken(meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		params := operations.NewUploadDeployFileParams()
+		params.DeployID = "deploy"
+		params.Path = "index.html"
+		params.FileBody = io.NopCloser(strings.NewReader("<html>file-contents</html>"))
+		meta.Operations.UploadDeployFile(params, meta.AuthInfo)
+	})
+
+	for _, expected := range []string{"Netlify API Request Details", "Netlify API Response Details", "request-id", redacted} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected the log to contain %q:\n%s", expected, out)
+		}
+	}
+	for _, secret := range []string{"secret-token", "file-contents"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestRedactingTransport_sensitiveKeys(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_NETLIFY", "")
+	t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+
+	cases := map[string]string{
+		"deploy_hook":  `{"id":"site","deploy_hook":"https://api.netlify.com/hooks/github/secret"}`,
+		"access_token": `{"id":"site","default_hooks_data":{"access_token":"secret"}}`,
+		"env":          `{"id":"site","build_settings":{"env":{"API_KEY":"secret"}}}`,
+		"email":        `{"id":"submission","email":"secret@example.com"}`,
+		"name":         `{"id":"submission","name":"secret"}`,
+		"body":         `{"id":"submission","body":"secret"}`,
+		"human_fields": `{"id":"submission","human_fields":{"Message":"secret"}}`,
+	}
+
+	for key, body := range cases {
+		out := testLoggedResponses(t, body, func(meta *Meta) {
+			if err := validateToken(meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		})
+
+		if !strings.Contains(out, "Netlify API Response Details") {
+			t.Fatalf("%s: expected the response to be logged:\n%s", key, out)
+		}
+		if strings.Contains(out, "secret") {
+			t.Errorf("%s: log contains its value:\n%s", key, out)
+		}
+	}
+}
+
+// A site as GetSite returns it, with every secret it carries.
+const testLoggedSiteFixture = `{
+	"id": "site",
+	"name": "test",
+	"url": "https://test.netlify.app",
+	"admin_url": "https://app.netlify.com/sites/test",
+	"custom_domain": "www.example.com",
+	"deploy_hook": "https://api.netlify.com/hooks/github/secret-deploy-hook",
+	"default_hooks_data": {"access_token": "secret-hooks-token"},
+	"build_settings": {
+		"provider": "github",
+		"repo_path": "owner/repo",
+		"repo_branch": "main",
+		"cmd": "npm run build",
+		"env": {"API_KEY": "secret-build-env"}
+	}
+}`
+
+// Build hooks as ListSiteBuildHooks returns them.
+const testLoggedBuildHooksFixture = `[
+	{"id": "hook", "title": "CMS", "branch": "main", "url": "https://api.netlify.com/build_hooks/secret-build-hook", "site_id": "site"}
+]`
+
+func TestRedactingTransport_fixtures(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_NETLIFY", "")
+	t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+
+	out := testLoggedResponses(t, testLoggedSiteFixture, func(meta *Meta) {
+		params := operations.NewGetSiteParams()
+		params.SiteID = "site"
+		if _, err := meta.Operations.GetSite(params, meta.AuthInfo); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	})
+	out += testLoggedResponses(t, testLoggedBuildHooksFixture, func(meta *Meta) {
+		params := operations.NewListSiteBuildHooksParams()
+		params.SiteID = "site"
+		if _, err := meta.Operations.ListSiteBuildHooks(params, meta.AuthInfo); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	})
+
+	for _, expected := range []string{"owner/repo", "npm run build", "CMS"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected the log to contain %q:\n%s", expected, out)
+		}
+	}
+	for _, secret := range []string{"secret-deploy-hook", "secret-hooks-token", "secret-build-env", "secret-build-hook"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestRedactingTransport_debugDisabled(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_NETLIFY", "")
+	t.Setenv("TF_LOG_PROVIDER", "")
+	t.Setenv("TF_LOG", "INFO")
+
+	out := testLoggedRequests(t, func(meta *Meta) {
+		if err := validateToken(meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	})
+	if strings.Contains(out, "API Request Details") {
+		t.Errorf("expected requests not to be dumped without DEBUG logs:\n%s", out)
+	}
+}
+
+func TestNewPorcelainLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := newPorcelainLogger(tflogtest.RootLogger(context.Background(), &out))
+	logger.WithField("deploy_id", "deploy").Debug("Uploading files")
+	logger.Warn("Retrying upload")
+
+	entries, err := tflogtest.MultilineJSONDecode(&out)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected both entries to be logged through tflog, got %v", entries)
+	}
+	if entries[0]["@level"] != "debug" || entries[0]["@message"] != "Uploading files" || entries[0]["deploy_id"] != "deploy" {
+		t.Errorf("expected the debug entry with its fields, got %v", entries[0])
+	}
+	if entries[1]["@level"] != "warn" || entries[1]["@message"] != "Retrying upload" {
+		t.Errorf("expected the warning, got %v", entries[1])
+	}
+}
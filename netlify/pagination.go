@@ -0,0 +1,55 @@
+package netlify
+
+// The page size used when listing paginated resources, unless the provider's
+// page_size says otherwise.
+const defaultPerPage int32 = 100
+
+// The largest page size the Netlify API accepts.
+const maxPerPage int32 = 100
+
+// Clamps a configured page size to the range the API accepts, falling back to
+// the default for unset values.
+func clampPerPage(n int) int32 {
+	if n <= 0 {
+		return defaultPerPage
+	}
+	if n > int(maxPerPage) {
+		return maxPerPage
+	}
+	return int32(n)
+}
+
+// Collects every item from a paginated list endpoint. fetch is called with
+// successive page numbers (starting at 1) until it returns a page with fewer
+// than perPage items.
+func paginate[T any](perPage int32, fetch func(page int32, perPage int32) ([]T, error)) ([]T, error) {
+	return paginateFiltered(perPage, 0, nil, fetch)
+}
+
+// Like paginate, but only keeps the items for which keep returns true (or all
+// of them if keep is nil) and stops requesting pages once limit items have
+// been collected. A limit of 0 collects everything.
+func paginateFiltered[T any](perPage int32, limit int, keep func(T) bool, fetch func(page int32, perPage int32) ([]T, error)) ([]T, error) {
+	var all []T
+	for page := int32(1); ; page++ {
+		items, err := fetch(page, perPage)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if keep != nil && !keep(item) {
+				continue
+			}
+
+			all = append(all, item)
+			if limit > 0 && len(all) == limit {
+				return all, nil
+			}
+		}
+
+		if int32(len(items)) < perPage {
+			return all, nil
+		}
+	}
+}
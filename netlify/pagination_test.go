@@ -0,0 +1,100 @@
+package netlify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	pages := map[int32][]int{
+		1: {1, 2},
+		2: {3, 4},
+		3: {5},
+	}
+
+	calls := 0
+	all, err := paginate(2, func(page int32, perPage int32) ([]int, error) {
+		calls++
+		if perPage != 2 {
+			t.Fatalf("unexpected per_page: %d", perPage)
+		}
+		return pages[page], nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected 3 page requests, got %d", calls)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 items, got %v", all)
+	}
+	for i, v := range all {
+		if v != i+1 {
+			t.Fatalf("items out of order: %v", all)
+		}
+	}
+}
+
+func TestPaginate_exactPage(t *testing.T) {
+	calls := 0
+	all, err := paginate(2, func(page int32, perPage int32) ([]int, error) {
+		calls++
+		if page == 1 {
+			return []int{1, 2}, nil
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if calls != 2 || len(all) != 2 {
+		t.Fatalf("expected 2 items over 2 requests, got %v over %d", all, calls)
+	}
+}
+
+func TestPaginate_error(t *testing.T) {
+	_, err := paginate(2, func(page int32, perPage int32) ([]int, error) {
+		if page == 2 {
+			return nil, errors.New("boom")
+		}
+		return []int{1, 2}, nil
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPaginateFiltered(t *testing.T) {
+	pages := map[int32][]int{
+		1: {1, 2, 3},
+		2: {4, 5, 6},
+		3: {7, 8, 9},
+	}
+
+	calls := 0
+	even, err := paginateFiltered(3, 2, func(v int) bool { return v%2 == 0 }, func(page int32, perPage int32) ([]int, error) {
+		calls++
+		return pages[page], nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected to stop after 2 pages, got %d", calls)
+	}
+	if len(even) != 2 || even[0] != 2 || even[1] != 4 {
+		t.Fatalf("unexpected items: %v", even)
+	}
+}
+
+func TestClampPerPage(t *testing.T) {
+	for n, expected := range map[int]int32{-1: 100, 0: 100, 1: 1, 50: 50, 100: 100, 1000: 100} {
+		if actual := clampPerPage(n); actual != expected {
+			t.Errorf("clampPerPage(%d) = %d, expected %d", n, actual, expected)
+		}
+	}
+}
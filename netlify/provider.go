@@ -2,9 +2,12 @@ package netlify
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/go-openapi/runtime"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
 )
 
 func init() {
@@ -40,9 +43,66 @@ func New(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("NETLIFY_BASE_URL", defaultBaseUrl),
 					Description: "The Netlify Base API URL",
 				},
+
+				"default_account_slug": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The slug of the team to use for sites, environment variables and account data sources that don't set their own `account_slug` (or `account_id`).",
+				},
+
+				"page_size": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     int(defaultPerPage),
+					Description: fmt.Sprintf("The number of items to request per page when listing sites, deploys or form submissions. Values above the API's maximum of %d are clamped to it.", maxPerPage),
+				},
+
+				"read_account_types": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Whether to export the plan of each `netlify_site`'s team as `account_type`. This costs one extra request per team on every refresh.",
+				},
+
+				"read_build_hooks": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Whether to export the build hooks of each `netlify_site` as `build_hooks`. This costs one extra request per site on every refresh.",
+				},
+
+				"read_last_deploy_states": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Whether to export the state of each `netlify_site`'s most recent deploy as `last_deploy_state`. This costs one extra request per site on every refresh.",
+				},
+
+				"validate_token": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Whether to check the token with a request for the current user while configuring the provider, so that a bad token fails before any resource is touched.",
+				},
+
+				"warn_on_missing_dns": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Whether to warn when refreshing a `netlify_site` whose `custom_domain` has no record in the team's Netlify DNS zone and isn't set up with `managed_dns` or `dns_zone`, as a reminder that setting the domain doesn't create any DNS records.",
+				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				"netlify_site": dataSourceSite(),
+				"netlify_account_capabilities":        dataSourceAccountCapabilities(),
+				"netlify_deploy_keys":                 dataSourceDeployKeys(),
+				"netlify_deploys":                     dataSourceDeploys(),
+				"netlify_dns_record":                  dataSourceDnsRecord(),
+				"netlify_form_submissions":            dataSourceFormSubmissions(),
+				"netlify_rate_limit":                  dataSourceRateLimit(),
+				"netlify_site":                        dataSourceSite(),
+				"netlify_site_asset_public_signature": dataSourceSiteAssetPublicSignature(),
+				"netlify_sites":                       dataSourceSites(),
+				"netlify_split_test":                  dataSourceSplitTest(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"netlify_build_hook":                 resourceBuildHook(),
@@ -50,10 +110,16 @@ func New(version string) func() *schema.Provider {
 				"netlify_deploy_key":                 resourceDeployKey(),
 				"netlify_hook":                       resourceHook(),
 				"netlify_site":                       resourceSite(),
+				"netlify_site_deploy":                resourceSiteDeploy(),
 				"netlify_environment_variable":       resourceEnvVar(),
 				"netlify_environment_variable_value": resourceEnvVarValue(),
+				"netlify_environment_variables":      resourceEnvVars(),
 				"netlify_dns_zone":                   resourceDnsZone(),
 				"netlify_dns_record":                 resourceDnsRecord(),
+				"netlify_dns_records":                resourceDnsRecords(),
+				"netlify_form_submission":            resourceFormSubmission(),
+				"netlify_ssl_certificate":            resourceSslCertificate(),
+				"netlify_ssl_certificate_wait":       resourceSslCertificateWait(),
 			},
 		}
 		p.ConfigureContextFunc = configure(version, p)
@@ -68,10 +134,67 @@ const defaultBaseUrl = "https://api.netlify.com/api/v1"
 func configure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
 	return func(c context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
 		config := Config{
-			Token:   d.Get("token").(string),
-			BaseURL: d.Get("base_url").(string),
+			Token:            d.Get("token").(string),
+			BaseURL:          d.Get("base_url").(string),
+			WarnOnMissingDNS: d.Get("warn_on_missing_dns").(bool),
+			PageSize:         d.Get("page_size").(int),
+			ReadAccountTypes: d.Get("read_account_types").(bool),
+
+			ReadLastDeployStates: d.Get("read_last_deploy_states").(bool),
+			ReadBuildHooks:       d.Get("read_build_hooks").(bool),
+
+			DefaultAccountSlug: d.Get("default_account_slug").(string),
 		}
-		client, err := config.Client()
-		return client, diag.FromErr(err)
+		client, err := config.Client(c)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		if d.Get("validate_token").(bool) {
+			if err := validateToken(client.(*Meta)); err != nil {
+				return nil, diag.FromErr(err)
+			}
+		}
+		return client, nil
+	}
+}
+
+// Checks that the token is accepted by fetching the current user. The
+// response body isn't decoded: the API returns a single user while the
+// generated client expects a list.
+func validateToken(meta *Meta) error {
+	err := meta.raw.GetCurrentUserRaw()
+	if v, ok := err.(*operations.GetCurrentUserDefault); ok && (v.Code() == 401 || v.Code() == 403) {
+		return fmt.Errorf("Authentication with Netlify failed, check the provider's token (or NETLIFY_TOKEN): %s", err)
+	}
+	if err != nil {
+		return fmt.Errorf("Error validating the Netlify token: %s", err)
+	}
+	return nil
+}
+
+func (o *transportRawOperations) GetCurrentUserRaw() error {
+	_, err := o.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getCurrentUser",
+		Method:             "GET",
+		PathPattern:        "/user",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             operations.NewGetCurrentUserParams(),
+		Reader:             currentUserStatusReader{},
+		AuthInfo:           o.authInfo,
+	})
+	return err
+}
+
+// Accepts any successful response, and reads everything else the same way
+// GetCurrentUser does.
+type currentUserStatusReader struct{}
+
+func (currentUserStatusReader) ReadResponse(resp runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	if resp.Code() == 200 {
+		return nil, nil
 	}
+	return (&operations.GetCurrentUserReader{}).ReadResponse(resp, consumer)
 }
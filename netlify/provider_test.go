@@ -1,13 +1,20 @@
 package netlify
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/go-openapi/runtime"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
 )
 
 // Use the same provider for all tests. This way it will be initialized so we
@@ -34,6 +41,64 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProvider_sensitiveAttributes(t *testing.T) {
+	p := New("dev")()
+	for resource, attrs := range map[string][]string{
+		"netlify_build_hook":                 {"url"},
+		"netlify_hook":                       {"data"},
+		"netlify_environment_variable_value": {"value"},
+	} {
+		for _, attr := range attrs {
+			if !p.ResourcesMap[resource].Schema[attr].Sensitive {
+				t.Errorf("%s.%s must be marked sensitive", resource, attr)
+			}
+		}
+	}
+}
+
+func TestProvider_validateToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":401,"message":"Access Denied"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"user","email":"user@example.com"}`))
+	}))
+	defer server.Close()
+
+	for token, valid := range map[string]bool{"good-token": true, "bad-token": false} {
+		config := Config{Token: token, BaseURL: server.URL + "/api/v1"}
+		client, err := config.Client(context.Background())
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		err = validateToken(client.(*Meta))
+		if valid && err != nil {
+			t.Errorf("expected %s to be accepted, got %s", token, err)
+		}
+		if !valid && (err == nil || !strings.Contains(err.Error(), "Authentication with Netlify failed")) {
+			t.Errorf("expected %s to fail authentication, got %v", token, err)
+		}
+	}
+}
+
+func TestProvider_validateTokenError(t *testing.T) {
+	for code, expected := range map[int]string{403: "Authentication with Netlify failed", 500: "Error validating the Netlify token"} {
+		ops := &testOperations{
+			getCurrentUserRaw: func() error {
+				return operations.NewGetCurrentUserDefault(code)
+			},
+		}
+
+		if err := validateToken(testMeta(ops)); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected a %d to fail with %q, got %v", code, expected, err)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("NETLIFY_TOKEN"); v == "" {
 		t.Fatal("NETLIFY_TOKEN must be set for acceptance tests")
@@ -50,3 +115,227 @@ func testAccAssert(msg string, f func() bool) resource.TestCheckFunc {
 		return errors.New("assertion failed: " + msg)
 	}
 }
+
+func TestProvider_pageSize(t *testing.T) {
+	var perPage []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		perPage = append(perPage, r.URL.Query().Get("per_page"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	for size, expected := range map[int]string{0: "100", 25: "25", 500: "100"} {
+		perPage = nil
+		config := Config{Token: "token", BaseURL: server.URL + "/api/v1", PageSize: size}
+		client, err := config.Client(context.Background())
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		d := dataSourceSites().TestResourceData()
+		if diags := dataSourceSitesRead(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("err: %v", diags)
+		}
+		if len(perPage) != 1 || perPage[0] != expected {
+			t.Errorf("expected page_size %d to request per_page=%s, got %v", size, expected, perPage)
+		}
+	}
+}
+
+// Returns the ResourceData of an update of r from state to the raw
+// configuration, so that HasChange sees the difference. CustomizeDiff isn't
+// run.
+func testResourceDataUpdate(t *testing.T, r *schema.Resource, state *terraform.InstanceState, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	sm := schema.InternalMap(r.Schema)
+	diff, err := sm.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil, nil, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := sm.Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return d
+}
+
+// Stubs the API operations a unit test needs. Calling an operation without a
+// stub panics on the nil embedded ClientService.
+type testOperations struct {
+	operations.ClientService
+
+	configureDNSForSite func(*operations.ConfigureDNSForSiteParams) (*operations.ConfigureDNSForSiteOK, error)
+	createDNSZone       func(*operations.CreateDNSZoneParams) (*operations.CreateDNSZoneCreated, error)
+	createHookBySiteID  func(*operations.CreateHookBySiteIDParams) (*operations.CreateHookBySiteIDCreated, error)
+	createEnvVars       func(*operations.CreateEnvVarsParams) (*operations.CreateEnvVarsCreated, error)
+	createSite          func(*operations.CreateSiteParams) (*operations.CreateSiteCreated, error)
+	createSiteInTeam    func(*operations.CreateSiteInTeamParams) (*operations.CreateSiteInTeamCreated, error)
+	deleteDNSRecord     func(*operations.DeleteDNSRecordParams) (*operations.DeleteDNSRecordNoContent, error)
+	deleteDNSZone       func(*operations.DeleteDNSZoneParams) (*operations.DeleteDNSZoneNoContent, error)
+	deleteHook          func(*operations.DeleteHookParams) (*operations.DeleteHookNoContent, error)
+	deleteEnvVar        func(*operations.DeleteEnvVarParams) (*operations.DeleteEnvVarNoContent, error)
+	deleteSite          func(*operations.DeleteSiteParams) (*operations.DeleteSiteNoContent, error)
+	getAccount          func(*operations.GetAccountParams) (*operations.GetAccountOK, error)
+	getDNSZone          func(*operations.GetDNSZoneParams) (*operations.GetDNSZoneOK, error)
+	getDNSZones         func(*operations.GetDNSZonesParams) (*operations.GetDNSZonesOK, error)
+	getDNSRecords       func(*operations.GetDNSRecordsParams) (*operations.GetDNSRecordsOK, error)
+	getSite             func(*operations.GetSiteParams) (*operations.GetSiteOK, error)
+	getHook             func(*operations.GetHookParams) (*operations.GetHookOK, error)
+	getEnvVar           func(*operations.GetEnvVarParams) (*operations.GetEnvVarOK, error)
+	getEnvVars          func(*operations.GetEnvVarsParams) (*operations.GetEnvVarsOK, error)
+	listDeployKeys      func(*operations.ListDeployKeysParams) (*operations.ListDeployKeysOK, error)
+	listSites           func(*operations.ListSitesParams) (*operations.ListSitesOK, error)
+	listSitesForAccount func(*operations.ListSitesForAccountParams) (*operations.ListSitesForAccountOK, error)
+	listSiteBuildHooks  func(*operations.ListSiteBuildHooksParams) (*operations.ListSiteBuildHooksOK, error)
+	listSiteDeploys     func(*operations.ListSiteDeploysParams) (*operations.ListSiteDeploysOK, error)
+
+	getAccountBuildStatus       func(*operations.GetAccountBuildStatusParams) (*operations.GetAccountBuildStatusOK, error)
+	getSiteAssetPublicSignature func(*operations.GetSiteAssetPublicSignatureParams) (*operations.GetSiteAssetPublicSignatureOK, error)
+	listAccountTypesForUser     func(*operations.ListAccountTypesForUserParams) (*operations.ListAccountTypesForUserOK, error)
+	showSiteTLSCertificate      func(*operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error)
+	updateEnvVar                func(*operations.UpdateEnvVarParams) (*operations.UpdateEnvVarOK, error)
+
+	// The requests that bypass the generated operations
+	getCurrentUserRaw func() error
+	getSiteRaw        func(siteID string) (json.RawMessage, error)
+	patchSiteRaw      func(siteID string, body map[string]interface{}) error
+}
+
+func (o *testOperations) ListDeployKeys(params *operations.ListDeployKeysParams, _ runtime.ClientAuthInfoWriter) (*operations.ListDeployKeysOK, error) {
+	return o.listDeployKeys(params)
+}
+
+func (o *testOperations) ListSites(params *operations.ListSitesParams, _ runtime.ClientAuthInfoWriter) (*operations.ListSitesOK, error) {
+	return o.listSites(params)
+}
+
+func (o *testOperations) ListSitesForAccount(params *operations.ListSitesForAccountParams, _ runtime.ClientAuthInfoWriter) (*operations.ListSitesForAccountOK, error) {
+	return o.listSitesForAccount(params)
+}
+
+func (o *testOperations) ListSiteBuildHooks(params *operations.ListSiteBuildHooksParams, _ runtime.ClientAuthInfoWriter) (*operations.ListSiteBuildHooksOK, error) {
+	return o.listSiteBuildHooks(params)
+}
+
+func (o *testOperations) ListSiteDeploys(params *operations.ListSiteDeploysParams, _ runtime.ClientAuthInfoWriter) (*operations.ListSiteDeploysOK, error) {
+	return o.listSiteDeploys(params)
+}
+
+func (o *testOperations) ShowSiteTLSCertificate(params *operations.ShowSiteTLSCertificateParams, _ runtime.ClientAuthInfoWriter) (*operations.ShowSiteTLSCertificateOK, error) {
+	return o.showSiteTLSCertificate(params)
+}
+
+func (o *testOperations) UpdateEnvVar(params *operations.UpdateEnvVarParams, _ runtime.ClientAuthInfoWriter) (*operations.UpdateEnvVarOK, error) {
+	return o.updateEnvVar(params)
+}
+
+func (o *testOperations) CreateHookBySiteID(params *operations.CreateHookBySiteIDParams, _ runtime.ClientAuthInfoWriter) (*operations.CreateHookBySiteIDCreated, error) {
+	return o.createHookBySiteID(params)
+}
+
+func (o *testOperations) DeleteHook(params *operations.DeleteHookParams, _ runtime.ClientAuthInfoWriter) (*operations.DeleteHookNoContent, error) {
+	return o.deleteHook(params)
+}
+
+func (o *testOperations) GetSite(params *operations.GetSiteParams, _ runtime.ClientAuthInfoWriter) (*operations.GetSiteOK, error) {
+	return o.getSite(params)
+}
+
+func (o *testOperations) GetHook(params *operations.GetHookParams, _ runtime.ClientAuthInfoWriter) (*operations.GetHookOK, error) {
+	return o.getHook(params)
+}
+
+func (o *testOperations) CreateEnvVars(params *operations.CreateEnvVarsParams, _ runtime.ClientAuthInfoWriter) (*operations.CreateEnvVarsCreated, error) {
+	return o.createEnvVars(params)
+}
+
+func (o *testOperations) CreateSite(params *operations.CreateSiteParams, _ runtime.ClientAuthInfoWriter) (*operations.CreateSiteCreated, error) {
+	return o.createSite(params)
+}
+
+func (o *testOperations) CreateSiteInTeam(params *operations.CreateSiteInTeamParams, _ runtime.ClientAuthInfoWriter) (*operations.CreateSiteInTeamCreated, error) {
+	return o.createSiteInTeam(params)
+}
+
+func (o *testOperations) DeleteDNSZone(params *operations.DeleteDNSZoneParams, _ runtime.ClientAuthInfoWriter) (*operations.DeleteDNSZoneNoContent, error) {
+	return o.deleteDNSZone(params)
+}
+
+func (o *testOperations) DeleteEnvVar(params *operations.DeleteEnvVarParams, _ runtime.ClientAuthInfoWriter) (*operations.DeleteEnvVarNoContent, error) {
+	return o.deleteEnvVar(params)
+}
+
+func (o *testOperations) DeleteSite(params *operations.DeleteSiteParams, _ runtime.ClientAuthInfoWriter) (*operations.DeleteSiteNoContent, error) {
+	return o.deleteSite(params)
+}
+
+func (o *testOperations) GetEnvVar(params *operations.GetEnvVarParams, _ runtime.ClientAuthInfoWriter) (*operations.GetEnvVarOK, error) {
+	return o.getEnvVar(params)
+}
+
+func (o *testOperations) GetEnvVars(params *operations.GetEnvVarsParams, _ runtime.ClientAuthInfoWriter) (*operations.GetEnvVarsOK, error) {
+	return o.getEnvVars(params)
+}
+
+func (o *testOperations) GetAccount(params *operations.GetAccountParams, _ runtime.ClientAuthInfoWriter) (*operations.GetAccountOK, error) {
+	return o.getAccount(params)
+}
+
+func (o *testOperations) GetAccountBuildStatus(params *operations.GetAccountBuildStatusParams, _ runtime.ClientAuthInfoWriter) (*operations.GetAccountBuildStatusOK, error) {
+	return o.getAccountBuildStatus(params)
+}
+
+func (o *testOperations) GetSiteAssetPublicSignature(params *operations.GetSiteAssetPublicSignatureParams, _ runtime.ClientAuthInfoWriter) (*operations.GetSiteAssetPublicSignatureOK, error) {
+	return o.getSiteAssetPublicSignature(params)
+}
+
+func (o *testOperations) ListAccountTypesForUser(params *operations.ListAccountTypesForUserParams, _ runtime.ClientAuthInfoWriter) (*operations.ListAccountTypesForUserOK, error) {
+	return o.listAccountTypesForUser(params)
+}
+
+func (o *testOperations) GetDNSZone(params *operations.GetDNSZoneParams, _ runtime.ClientAuthInfoWriter) (*operations.GetDNSZoneOK, error) {
+	return o.getDNSZone(params)
+}
+
+func (o *testOperations) GetDNSZones(params *operations.GetDNSZonesParams, _ runtime.ClientAuthInfoWriter) (*operations.GetDNSZonesOK, error) {
+	return o.getDNSZones(params)
+}
+
+func (o *testOperations) CreateDNSZone(params *operations.CreateDNSZoneParams, _ runtime.ClientAuthInfoWriter) (*operations.CreateDNSZoneCreated, error) {
+	return o.createDNSZone(params)
+}
+
+func (o *testOperations) ConfigureDNSForSite(params *operations.ConfigureDNSForSiteParams, _ runtime.ClientAuthInfoWriter) (*operations.ConfigureDNSForSiteOK, error) {
+	return o.configureDNSForSite(params)
+}
+
+func (o *testOperations) DeleteDNSRecord(params *operations.DeleteDNSRecordParams, _ runtime.ClientAuthInfoWriter) (*operations.DeleteDNSRecordNoContent, error) {
+	return o.deleteDNSRecord(params)
+}
+
+func (o *testOperations) GetDNSRecords(params *operations.GetDNSRecordsParams, _ runtime.ClientAuthInfoWriter) (*operations.GetDNSRecordsOK, error) {
+	return o.getDNSRecords(params)
+}
+
+func (o *testOperations) GetCurrentUserRaw() error {
+	return o.getCurrentUserRaw()
+}
+
+func (o *testOperations) GetSiteRaw(siteID string) (json.RawMessage, error) {
+	return o.getSiteRaw(siteID)
+}
+
+func (o *testOperations) PatchSiteRaw(siteID string, body map[string]interface{}) error {
+	return o.patchSiteRaw(siteID, body)
+}
+
+// Returns a Meta whose API operations, and raw requests if ops stubs them too,
+// are the given stubs.
+func testMeta(ops operations.ClientService) *Meta {
+	meta := &Meta{Operations: ops, perPage: defaultPerPage}
+	meta.raw, _ = ops.(rawOperations)
+	meta.accounts = newAccountCache(meta.fetchAccount)
+	return meta
+}
@@ -0,0 +1,84 @@
+package netlify
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The rate limit of the token as of the most recent API response that
+// reported it.
+type rateLimit struct {
+	mu sync.Mutex
+
+	known     bool
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// Records the rate limit headers of a response, returning whether it had any.
+func (l *rateLimit) record(h http.Header) bool {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return false
+	}
+	limit, _ := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	var reset time.Time
+	if v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(v, 0).UTC()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.known, l.limit, l.remaining, l.reset = true, limit, remaining, reset
+	return true
+}
+
+// Returns the most recently recorded rate limit, and false if no response
+// has reported one yet.
+func (l *rateLimit) get() (int, int, time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit, l.remaining, l.reset, l.known
+}
+
+// Records the rate limit reported by each API response and logs it at DEBUG
+// level, so large applies can be tuned against the remaining quota. Only the
+// path of the request is logged, since some operations send secrets such as
+// TLS keys as query parameters.
+type rateLimitTransport struct {
+	ctx       context.Context
+	limits    *rateLimit
+	transport http.RoundTripper
+}
+
+func newRateLimitTransport(ctx context.Context, limits *rateLimit, t http.RoundTripper) http.RoundTripper {
+	return &rateLimitTransport{ctx, limits, t}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if t.limits.record(resp.Header) {
+		limit, remaining, reset, _ := t.limits.get()
+		fields := map[string]interface{}{
+			"http_path":            req.URL.Path,
+			"rate_limit_limit":     limit,
+			"rate_limit_remaining": remaining,
+		}
+		if !reset.IsZero() {
+			fields["rate_limit_reset"] = reset.Format(time.RFC3339)
+		}
+		tflog.Debug(t.ctx, "Netlify API rate limit", fields)
+	}
+
+	return resp, nil
+}
@@ -67,7 +67,7 @@ func resourceBranchDeployCreate(d *schema.ResourceData, metaRaw interface{}) err
 			},
 		},
 	}
-	_, err = meta.Netlify.Operations.UpdateSite(patch, meta.AuthInfo)
+	_, err = meta.Operations.UpdateSite(patch, meta.AuthInfo)
 
 	if err != nil {
 		return err
@@ -88,7 +88,7 @@ func resourceBranchDeployRead(d *schema.ResourceData, metaRaw interface{}) error
 
 	params := operations.NewGetSiteParams()
 	params.SiteID = siteId
-	resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetSite(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
@@ -139,7 +139,7 @@ func resourceBranchDeployUpdate(d *schema.ResourceData, metaRaw interface{}) err
 		},
 	}
 
-	_, err = meta.Netlify.Operations.UpdateSite(params, meta.AuthInfo)
+	_, err = meta.Operations.UpdateSite(params, meta.AuthInfo)
 
 	if err != nil {
 		return err
@@ -182,7 +182,7 @@ func resourceBranchDeployDelete(d *schema.ResourceData, metaRaw interface{}) err
 		},
 	}
 
-	_, err = meta.Netlify.Operations.UpdateSite(params, meta.AuthInfo)
+	_, err = meta.Operations.UpdateSite(params, meta.AuthInfo)
 
 	if err != nil {
 		return err
@@ -194,7 +194,7 @@ func resourceBranchDeployDelete(d *schema.ResourceData, metaRaw interface{}) err
 func resourceBranchDeploy_getBranchAndBranches(d *schema.ResourceData, meta *Meta) (string, []string, error) {
 	params := operations.NewGetSiteParams()
 	params.SiteID = d.Get("site_id").(string)
-	resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetSite(params, meta.AuthInfo)
 	if err != nil {
 		return "", nil, err
 	}
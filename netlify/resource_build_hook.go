@@ -1,6 +1,10 @@
 package netlify
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
@@ -13,7 +17,7 @@ func resourceBuildHook() *schema.Resource {
 		Update: resourceBuildHookUpdate,
 		Delete: resourceBuildHookDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceBuildHookImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -33,8 +37,9 @@ func resourceBuildHook() *schema.Resource {
 			},
 
 			"url": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
 			},
 		},
 	}
@@ -46,7 +51,7 @@ func resourceBuildHookCreate(d *schema.ResourceData, metaRaw interface{}) error
 	params.BuildHook = resourceBuildHookSetup_struct(d)
 
 	meta := metaRaw.(*Meta)
-	resp, err := meta.Netlify.Operations.CreateSiteBuildHook(params, meta.AuthInfo)
+	resp, err := meta.Operations.CreateSiteBuildHook(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
@@ -60,7 +65,7 @@ func resourceBuildHookRead(d *schema.ResourceData, metaRaw interface{}) error {
 	params := operations.NewGetSiteBuildHookParams()
 	params.ID = d.Id()
 	params.SiteID = d.Get("site_id").(string)
-	resp, err := meta.Netlify.Operations.GetSiteBuildHook(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetSiteBuildHook(params, meta.AuthInfo)
 	if err != nil {
 		// If it is a 404 it was removed remotely
 		if v, ok := err.(*operations.GetSiteBuildHookDefault); ok && v.Code() == 404 {
@@ -87,7 +92,7 @@ func resourceBuildHookUpdate(d *schema.ResourceData, metaRaw interface{}) error
 	params.BuildHook = resourceBuildHookSetup_struct(d)
 
 	meta := metaRaw.(*Meta)
-	_, err := meta.Netlify.Operations.UpdateSiteBuildHook(params, meta.AuthInfo)
+	_, err := meta.Operations.UpdateSiteBuildHook(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
@@ -100,10 +105,84 @@ func resourceBuildHookDelete(d *schema.ResourceData, metaRaw interface{}) error
 	params := operations.NewDeleteSiteBuildHookParams()
 	params.ID = d.Id()
 	params.SiteID = d.Get("site_id").(string)
-	_, err := meta.Netlify.Operations.DeleteSiteBuildHook(params, meta.AuthInfo)
+	_, err := meta.Operations.DeleteSiteBuildHook(params, meta.AuthInfo)
 	return err
 }
 
+// Imports a build hook as site_id/title, site_id/hook_id or site_id/url. Hooks
+// created in the UI are easier to find by title, and hooks used from CI by
+// their URL, so the ID is looked up among the site's hooks. The URL only holds
+// the hook ID, which is why the site is needed as well.
+func resourceBuildHookImport(d *schema.ResourceData, metaRaw interface{}) ([]*schema.ResourceData, error) {
+	meta := metaRaw.(*Meta)
+	if _, ok := resourceBuildHook_idFromURL(d.Id()); ok {
+		return nil, fmt.Errorf("A build hook URL doesn't include the site, import the hook as site_id/url instead")
+	}
+
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Invalid build hook import ID %q, expected site_id/title", d.Id())
+	}
+	siteID, title := parts[0], parts[1]
+	hookID, byURL := resourceBuildHook_idFromURL(title)
+
+	params := operations.NewListSiteBuildHooksParams()
+	params.SiteID = siteID
+	resp, err := meta.Operations.ListSiteBuildHooks(params, meta.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*models.BuildHook
+	for _, hook := range resp.Payload {
+		if byURL {
+			if hook.ID == hookID {
+				matches = []*models.BuildHook{hook}
+				break
+			}
+			continue
+		}
+		if hook.ID == title {
+			matches = []*models.BuildHook{hook}
+			break
+		}
+		if hook.Title == title {
+			matches = append(matches, hook)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if byURL {
+			return nil, fmt.Errorf("No build hook with the given URL found on site %s", siteID)
+		}
+		return nil, fmt.Errorf("No build hook titled %q found on site %s", title, siteID)
+	case 1:
+	default:
+		return nil, fmt.Errorf("Found %d build hooks titled %q on site %s; import one of them as site_id/hook_id instead", len(matches), title, siteID)
+	}
+
+	d.Set("site_id", siteID)
+	d.SetId(matches[0].ID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// Returns the hook ID of a build hook URL such as
+// https://api.netlify.com/build_hooks/<hook_id>, ignoring any query parameters
+// added to trigger the hook.
+func resourceBuildHook_idFromURL(s string) (string, bool) {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] != "build_hooks" || parts[len(parts)-1] == "" {
+		return "", false
+	}
+	return parts[len(parts)-1], true
+}
+
 // Returns the BuildHook structure that can be used for creation or updating.
 func resourceBuildHookSetup_struct(d *schema.ResourceData) *models.BuildHookSetup {
 	return &models.BuildHookSetup{
@@ -29,6 +29,103 @@ func TestAccBuildHook(t *testing.T) {
 	})
 }
 
+func TestAccBuildHook_import(t *testing.T) {
+	resourceName := "netlify_build_hook.test"
+
+	importID := func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("Not Found: %s", resourceName)
+		}
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["site_id"], rs.Primary.Attributes["title"]), nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckBuildHookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildHookConfig,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: importID,
+			},
+		},
+	})
+}
+
+func TestAccBuildHook_importURL(t *testing.T) {
+	resourceName := "netlify_build_hook.test"
+
+	importID := func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("Not Found: %s", resourceName)
+		}
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["site_id"], rs.Primary.Attributes["url"]), nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckBuildHookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildHookConfig,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: importID,
+			},
+		},
+	})
+}
+
+func TestResourceBuildHookImport_url(t *testing.T) {
+	ops := &testOperations{
+		listSiteBuildHooks: func(params *operations.ListSiteBuildHooksParams) (*operations.ListSiteBuildHooksOK, error) {
+			return &operations.ListSiteBuildHooksOK{Payload: []*models.BuildHook{
+				{ID: "first", Title: "first"},
+				{ID: "abc123", Title: "second"},
+			}}, nil
+		},
+	}
+
+	for id, expected := range map[string]string{
+		"site/https://api.netlify.com/build_hooks/abc123":                      "abc123",
+		"site/https://api.netlify.com/build_hooks/abc123?trigger_title=deploy": "abc123",
+		"site/second": "abc123",
+	} {
+		d := resourceBuildHook().TestResourceData()
+		d.SetId(id)
+		if _, err := resourceBuildHookImport(d, testMeta(ops)); err != nil {
+			t.Fatalf("%s: %s", id, err)
+		}
+		if d.Id() != expected || d.Get("site_id").(string) != "site" {
+			t.Errorf("expected %s to import hook %s of site, got %s of %s", id, expected, d.Id(), d.Get("site_id"))
+		}
+	}
+
+	for _, id := range []string{
+		"site/https://api.netlify.com/build_hooks/missing",
+		"https://api.netlify.com/build_hooks/abc123",
+	} {
+		d := resourceBuildHook().TestResourceData()
+		d.SetId(id)
+		if _, err := resourceBuildHookImport(d, testMeta(ops)); err == nil {
+			t.Errorf("expected importing %s to fail", id)
+		}
+	}
+}
+
 func TestAccBuildHook_disappears(t *testing.T) {
 	var hook models.BuildHook
 	resourceName := "netlify_build_hook.test"
@@ -38,7 +135,7 @@ func TestAccBuildHook_disappears(t *testing.T) {
 		params := operations.NewDeleteSiteBuildHookParams()
 		params.ID = hook.ID
 		params.SiteID = hook.SiteID
-		_, err := meta.Netlify.Operations.DeleteSiteBuildHook(params, meta.AuthInfo)
+		_, err := meta.Operations.DeleteSiteBuildHook(params, meta.AuthInfo)
 		return err
 	}
 
@@ -106,7 +203,7 @@ func testAccCheckBuildHookExists(n string, hook *models.BuildHook) resource.Test
 		params := operations.NewGetSiteBuildHookParams()
 		params.ID = rs.Primary.ID
 		params.SiteID = rs.Primary.Attributes["site_id"]
-		resp, err := meta.Netlify.Operations.GetSiteBuildHook(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetSiteBuildHook(params, meta.AuthInfo)
 		if err != nil {
 			return err
 		}
@@ -126,7 +223,7 @@ func testAccCheckBuildHookDestroy(s *terraform.State) error {
 		params := operations.NewGetSiteBuildHookParams()
 		params.ID = rs.Primary.ID
 		params.SiteID = rs.Primary.Attributes["site_id"]
-		resp, err := meta.Netlify.Operations.GetSiteBuildHook(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetSiteBuildHook(params, meta.AuthInfo)
 		if err == nil && resp.Payload != nil {
 			return fmt.Errorf("BuildHook still exists: %s", rs.Primary.ID)
 		}
@@ -5,6 +5,8 @@ import (
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
 )
 
+// Deploy keys can't be changed, so a new key always comes with a new ID, which
+// sites referencing the key through deploy_key_id pick up in place.
 func resourceDeployKey() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDeployKeyCreate,
@@ -16,8 +18,9 @@ func resourceDeployKey() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"public_key": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The public key to add to the repo on the git provider. It changes whenever the key is recreated.",
 			},
 		},
 	}
@@ -26,7 +29,7 @@ func resourceDeployKey() *schema.Resource {
 func resourceDeployKeyCreate(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
 
-	resp, err := meta.Netlify.Operations.CreateDeployKey(
+	resp, err := meta.Operations.CreateDeployKey(
 		operations.NewCreateDeployKeyParams(), meta.AuthInfo)
 	if err != nil {
 		return err
@@ -41,7 +44,7 @@ func resourceDeployKeyRead(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
 	params := operations.NewGetDeployKeyParams()
 	params.KeyID = d.Id()
-	resp, err := meta.Netlify.Operations.GetDeployKey(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetDeployKey(params, meta.AuthInfo)
 	if err != nil {
 		// Deleted remotely
 		if v, ok := err.(*operations.GetDeployKeyDefault); ok && v.Code() == 404 {
@@ -60,6 +63,6 @@ func resourceDeployKeyDelete(d *schema.ResourceData, metaRaw interface{}) error
 	meta := metaRaw.(*Meta)
 	params := operations.NewDeleteDeployKeyParams()
 	params.KeyID = d.Id()
-	_, err := meta.Netlify.Operations.DeleteDeployKey(params, meta.AuthInfo)
+	_, err := meta.Operations.DeleteDeployKey(params, meta.AuthInfo)
 	return err
 }
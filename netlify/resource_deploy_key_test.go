@@ -41,7 +41,7 @@ func TestAccDeployKey_disappears(t *testing.T) {
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewDeleteDeployKeyParams()
 		params.KeyID = key.ID
-		_, err := meta.Netlify.Operations.DeleteDeployKey(params, meta.AuthInfo)
+		_, err := meta.Operations.DeleteDeployKey(params, meta.AuthInfo)
 		return err
 	}
 
@@ -62,6 +62,38 @@ func TestAccDeployKey_disappears(t *testing.T) {
 	})
 }
 
+func TestAccDeployKey_recreate(t *testing.T) {
+	var before, after models.DeployKey
+	resourceName := "netlify_deploy_key.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDeployKeyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeployKeyConfig_site,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeployKeyExists(resourceName, &before),
+					resource.TestCheckResourceAttrPair("netlify_site.test", "repo.0.deploy_key_id", resourceName, "id"),
+				),
+			},
+
+			{
+				Config: testAccDeployKeyConfig_site,
+				Taint:  []string{resourceName},
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeployKeyExists(resourceName, &after),
+					resource.TestCheckResourceAttrPair("netlify_site.test", "repo.0.deploy_key_id", resourceName, "id"),
+					testAccAssert("key replaced", func() bool {
+						return before.ID != after.ID && before.PublicKey != after.PublicKey
+					}),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckDeployKeyExists(n string, key *models.DeployKey) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -76,7 +108,7 @@ func testAccCheckDeployKeyExists(n string, key *models.DeployKey) resource.TestC
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewGetDeployKeyParams()
 		params.KeyID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetDeployKey(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetDeployKey(params, meta.AuthInfo)
 		if err != nil {
 			return err
 		}
@@ -95,7 +127,7 @@ func testAccCheckDeployKeyDestroy(s *terraform.State) error {
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewGetDeployKeyParams()
 		params.KeyID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetDeployKey(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetDeployKey(params, meta.AuthInfo)
 		if err == nil && resp.Payload != nil {
 			return fmt.Errorf("Resource still exists: %s", rs.Primary.ID)
 		}
@@ -113,3 +145,20 @@ func testAccCheckDeployKeyDestroy(s *terraform.State) error {
 }
 
 var testAccDeployKeyConfig = `resource "netlify_deploy_key" "test" {}`
+
+var testAccDeployKeyConfig_site = `
+resource "netlify_deploy_key" "test" {
+	lifecycle {
+		create_before_destroy = true
+	}
+}
+
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		deploy_key_id = netlify_deploy_key.test.id
+	}
+}
+`
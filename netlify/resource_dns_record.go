@@ -1,18 +1,38 @@
 package netlify
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
 )
 
+// Manages a single record of a Netlify DNS zone. The DNS API can only create
+// and delete records, so every argument that describes the record forces a
+// new one. For types that allow several values per hostname, such as A or
+// TXT records, `create_before_destroy` keeps the hostname resolving while a
+// value changes.
 func resourceDnsRecord() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDnsRecordCreate,
 		Read:   resourceDnsRecordRead,
+		Update: resourceDnsRecordUpdate,
 		Delete: resourceDnsRecordDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, metaRaw interface{}) error {
+			return resourceDnsRecord_checkSiteID(d.Get("type").(string), d.Get("site_id").(string))
+		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceDnsRecordImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -23,9 +43,16 @@ func resourceDnsRecord() *schema.Resource {
 			},
 
 			"hostname": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: resourceDnsRecord_hostnameDiffSuppress,
+			},
+
+			"zone_name": {
+				Description: "The name of the record's DNS zone, used to tell whether a hostname relative to the zone and a fully qualified one are the same name.",
+				Type:        schema.TypeString,
+				Computed:    true,
 			},
 
 			"type": {
@@ -35,30 +62,74 @@ func resourceDnsRecord() *schema.Resource {
 			},
 
 			"value": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"value", "site_id"},
+				Description:  "The value of the record. Netlify can't update records, so changing it replaces the record.",
+			},
+
+			"site_id": {
+				Description: "The ID of a Netlify site to point an `ALIAS` or `CNAME` record at. The record's value is set to the site's Netlify subdomain, and the record is replaced when the site is renamed. Read back on import when the value is a site's Netlify subdomain.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+
+			"wait_for_propagation": {
+				Description: "Whether to wait after creating the record until the zone's Netlify name servers answer for it. The wait is bounded by the create timeout.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
 			},
 		},
 	}
 }
 
 func resourceDnsRecordCreate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	zone, err := resourceDnsRecord_zone(meta, d.Get("zone_id").(string))
+	if err != nil {
+		return err
+	}
+
+	value := d.Get("value").(string)
+	if siteID := d.Get("site_id").(string); siteID != "" {
+		value, err = getSiteDefaultDomain(meta, siteID)
+		if err != nil {
+			return fmt.Errorf("Error resolving the Netlify subdomain of site %s: %s", siteID, err)
+		}
+	}
+
+	hostname := normalizeDnsHostname(d.Get("hostname").(string), zone.Name)
 	params := operations.NewCreateDNSRecordParams()
 	params.ZoneID = d.Get("zone_id").(string)
 	params.DNSRecord = &models.DNSRecordCreate{
-		Hostname: d.Get("hostname").(string),
+		Hostname: hostname,
 		Type:     d.Get("type").(string),
-		Value:    d.Get("value").(string),
+		Value:    value,
 	}
 
-	meta := metaRaw.(*Meta)
-	resp, err := meta.Netlify.Operations.CreateDNSRecord(params, meta.AuthInfo)
+	resp, err := meta.Operations.CreateDNSRecord(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
 
 	d.SetId(resp.Payload.ID)
+
+	if d.Get("wait_for_propagation").(bool) {
+		resolver := resourceDnsRecord_resolver(zone.DNSServers)
+		recordType := d.Get("type").(string)
+		err := resourceDnsRecord_waitForPropagation(d.Timeout(schema.TimeoutCreate), func() (bool, error) {
+			return resourceDnsRecord_resolves(resolver, hostname, recordType)
+		})
+		if err != nil {
+			return fmt.Errorf("Error waiting for DNS record %s (%s) to propagate: %s", hostname, d.Id(), err)
+		}
+	}
+
 	return resourceDnsRecordRead(d, metaRaw)
 }
 
@@ -67,7 +138,7 @@ func resourceDnsRecordRead(d *schema.ResourceData, metaRaw interface{}) error {
 	params := operations.NewGetIndividualDNSRecordParams()
 	params.ZoneID = d.Get("zone_id").(string)
 	params.DNSRecordID = d.Id()
-	resp, err := meta.Netlify.Operations.GetIndividualDNSRecord(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetIndividualDNSRecord(params, meta.AuthInfo)
 	if err != nil {
 		// If it is a 404 it was removed remotely
 		if v, ok := err.(*operations.GetIndividualDNSRecordDefault); ok && v.Code() == 404 {
@@ -78,20 +149,313 @@ func resourceDnsRecordRead(d *schema.ResourceData, metaRaw interface{}) error {
 		return err
 	}
 
-	zone := resp.Payload
-	d.Set("site_id", zone.SiteID)
-	d.Set("hostname", zone.Hostname)
-	d.Set("type", zone.Type)
-	d.Set("value", zone.Value)
+	record := resp.Payload
+	d.Set("type", record.Type)
+	d.Set("value", record.Value)
+
+	// Renaming a site changes its Netlify subdomain. Once the record no longer
+	// points at it, site_id is cleared so that the record is replaced with one
+	// that does.
+	if siteID := d.Get("site_id").(string); siteID != "" {
+		domain, err := getSiteDefaultDomain(meta, siteID)
+		if err != nil {
+			if v, ok := err.(*operations.GetSiteDefault); !ok || v.Code() != 404 {
+				return fmt.Errorf("Error resolving the Netlify subdomain of site %s: %s", siteID, err)
+			}
+		}
+		if !strings.EqualFold(strings.TrimSuffix(record.Value, "."), domain) {
+			d.Set("site_id", "")
+		}
+	}
+
+	// Keep the hostname in the form it was configured in (relative to the
+	// zone or fully qualified) as long as it refers to the same name. An
+	// imported record has no form yet and gets the relative one.
+	zone, err := resourceDnsRecord_zoneName(meta, params.ZoneID)
+	if err != nil {
+		return err
+	}
+	d.Set("zone_name", zone)
+	current := d.Get("hostname").(string)
+	if current == "" {
+		d.Set("hostname", relativeDnsHostname(record.Hostname, zone))
+	} else if normalizeDnsHostname(current, zone) != normalizeDnsHostname(record.Hostname, zone) {
+		d.Set("hostname", record.Hostname)
+	}
 
 	return nil
 }
 
+// Only wait_for_propagation can change without replacing the record, and it
+// only applies while creating one, so there is nothing to send.
+func resourceDnsRecordUpdate(d *schema.ResourceData, metaRaw interface{}) error {
+	return resourceDnsRecordRead(d, metaRaw)
+}
+
 func resourceDnsRecordDelete(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
 	params := operations.NewDeleteDNSRecordParams()
 	params.ZoneID = d.Get("zone_id").(string)
 	params.DNSRecordID = d.Id()
-	_, err := meta.Netlify.Operations.DeleteDNSRecord(params, meta.AuthInfo)
+	_, err := meta.Operations.DeleteDNSRecord(params, meta.AuthInfo)
 	return err
 }
+
+// Imports a record either as zone_id/record_id or as zone_id/hostname/type,
+// in which case the record ID is looked up in the zone. wait_for_propagation
+// only matters on create, so it is set to its default to keep imported records
+// from planning a replacement, and site_id is restored for records that point
+// at a site.
+func resourceDnsRecordImport(d *schema.ResourceData, metaRaw interface{}) ([]*schema.ResourceData, error) {
+	meta := metaRaw.(*Meta)
+	parts := strings.Split(d.Id(), "/")
+
+	switch len(parts) {
+	case 2:
+		d.Set("zone_id", parts[0])
+		d.SetId(parts[1])
+	case 3:
+		zoneID, hostname, recordType := parts[0], parts[1], parts[2]
+		zone, err := resourceDnsRecord_zoneName(meta, zoneID)
+		if err != nil {
+			return nil, err
+		}
+
+		params := operations.NewGetDNSRecordsParams()
+		params.ZoneID = zoneID
+		resp, err := meta.Operations.GetDNSRecords(params, meta.AuthInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := resourceDnsRecord_matching(resp.Payload, zone, hostname, recordType, "")
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("No %s record found for %s in zone %s", recordType, normalizeDnsHostname(hostname, zone), zoneID)
+		case 1:
+		default:
+			return nil, fmt.Errorf("Found %d %s records for %s in zone %s; import one of them as zone_id/record_id instead", len(matches), recordType, normalizeDnsHostname(hostname, zone), zoneID)
+		}
+
+		d.Set("zone_id", zoneID)
+		d.Set("hostname", hostname)
+		d.SetId(matches[0].ID)
+	default:
+		return nil, fmt.Errorf("Invalid DNS record import ID %q, expected zone_id/record_id or zone_id/hostname/type", d.Id())
+	}
+
+	siteID, err := resourceDnsRecord_importSiteID(meta, d.Get("zone_id").(string), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("site_id", siteID)
+
+	d.Set("wait_for_propagation", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// Returns the ID of the site whose Netlify subdomain an ALIAS or CNAME record
+// points at, or "" if it doesn't point at one. Netlify looks sites up by
+// domain as well as by ID, and the default domain tells a site's subdomain
+// apart from its custom domain.
+func resourceDnsRecord_importSiteID(meta *Meta, zoneID string, recordID string) (string, error) {
+	params := operations.NewGetIndividualDNSRecordParams()
+	params.ZoneID = zoneID
+	params.DNSRecordID = recordID
+	resp, err := meta.Operations.GetIndividualDNSRecord(params, meta.AuthInfo)
+	if err != nil {
+		return "", err
+	}
+
+	record := resp.Payload
+	value := strings.TrimSuffix(strings.ToLower(record.Value), ".")
+	switch strings.ToUpper(record.Type) {
+	case "ALIAS", "CNAME":
+		if value == "" {
+			return "", nil
+		}
+	default:
+		return "", nil
+	}
+
+	site, fields, err := getSite(meta, value)
+	if err != nil {
+		if v, ok := err.(*operations.GetSiteDefault); ok && v.Code() == 404 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if !strings.EqualFold(fields.DefaultDomain, value) {
+		return "", nil
+	}
+	return site.ID, nil
+}
+
+// Only records that can point at a hostname can be pointed at a site.
+func resourceDnsRecord_checkSiteID(recordType string, siteID string) error {
+	if siteID == "" {
+		return nil
+	}
+	switch strings.ToUpper(recordType) {
+	case "ALIAS", "CNAME":
+		return nil
+	}
+	return fmt.Errorf("site_id can only be used with ALIAS and CNAME records, not %s", recordType)
+}
+
+// Returns the records with the given hostname and type, and value if it isn't
+// empty.
+func resourceDnsRecord_matching(records []*models.DNSRecord, zone string, hostname string, recordType string, value string) []*models.DNSRecord {
+	hostname = normalizeDnsHostname(hostname, zone)
+
+	var matches []*models.DNSRecord
+	for _, record := range records {
+		if normalizeDnsHostname(record.Hostname, zone) != hostname || !strings.EqualFold(record.Type, recordType) {
+			continue
+		}
+		if value != "" && record.Value != value {
+			continue
+		}
+		matches = append(matches, record)
+	}
+	return matches
+}
+
+// Returns the DNS zone with the given ID.
+func resourceDnsRecord_zone(meta *Meta, zoneID string) (*models.DNSZone, error) {
+	params := operations.NewGetDNSZoneParams()
+	params.ZoneID = zoneID
+	resp, err := meta.Operations.GetDNSZone(params, meta.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Payload, nil
+}
+
+// Returns the name of the DNS zone with the given ID.
+func resourceDnsRecord_zoneName(meta *Meta, zoneID string) (string, error) {
+	zone, err := resourceDnsRecord_zone(meta, zoneID)
+	if err != nil {
+		return "", err
+	}
+	return zone.Name, nil
+}
+
+// Polls resolved with an exponentially growing interval until it reports the
+// record as resolved, it fails, or timeout passes.
+func resourceDnsRecord_waitForPropagation(timeout time.Duration, resolved func() (bool, error)) error {
+	conf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"resolved"},
+		Timeout: timeout,
+		Refresh: func() (interface{}, string, error) {
+			ok, err := resolved()
+			if err != nil {
+				return nil, "", err
+			}
+			if !ok {
+				return "pending", "pending", nil
+			}
+			return "resolved", "resolved", nil
+		},
+	}
+
+	_, err := conf.WaitForState()
+	return err
+}
+
+// Returns a resolver that queries the first of the given name servers, or the
+// system resolver if there are none.
+func resourceDnsRecord_resolver(servers []string) *net.Resolver {
+	if len(servers) == 0 {
+		return net.DefaultResolver
+	}
+
+	server := net.JoinHostPort(strings.TrimSuffix(servers[0], "."), "53")
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// Reports whether the resolver answers for hostname. Record types the Go
+// resolver can't look up are reported as resolved, since the record has
+// already been created by then.
+func resourceDnsRecord_resolves(resolver *net.Resolver, hostname string, recordType string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var found bool
+	var err error
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA":
+		var addrs []string
+		addrs, err = resolver.LookupHost(ctx, hostname)
+		found = len(addrs) > 0
+	case "CNAME":
+		var cname string
+		cname, err = resolver.LookupCNAME(ctx, hostname)
+		found = cname != ""
+	case "MX":
+		var mxs []*net.MX
+		mxs, err = resolver.LookupMX(ctx, hostname)
+		found = len(mxs) > 0
+	case "NS":
+		var nss []*net.NS
+		nss, err = resolver.LookupNS(ctx, hostname)
+		found = len(nss) > 0
+	case "TXT":
+		var txts []string
+		txts, err = resolver.LookupTXT(ctx, hostname)
+		found = len(txts) > 0
+	default:
+		return true, nil
+	}
+
+	// Not found or timeouts just mean the record hasn't propagated yet
+	if _, ok := err.(*net.DNSError); ok {
+		return false, nil
+	}
+	return found, err
+}
+
+// Suppresses the diff between two forms of the same hostname, e.g. `www` in
+// the configuration and `www.example.com` read back after an import, so it
+// doesn't replace the record.
+func resourceDnsRecord_hostnameDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	zone := d.Get("zone_name").(string)
+	if zone == "" || old == "" {
+		return false
+	}
+	return normalizeDnsHostname(old, zone) == normalizeDnsHostname(new, zone)
+}
+
+// Returns the fully qualified form of a hostname within a zone, so that
+// `www`, `www.example.com` and `www.example.com.` all refer to the same
+// record. `@` and the empty string refer to the zone apex.
+func normalizeDnsHostname(hostname string, zone string) string {
+	hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	zone = strings.TrimSuffix(strings.ToLower(zone), ".")
+
+	if hostname == "" || hostname == "@" || hostname == zone {
+		return zone
+	}
+	if strings.HasSuffix(hostname, "."+zone) {
+		return hostname
+	}
+	return hostname + "." + zone
+}
+
+// Returns the form of a hostname relative to its zone, with `@` for the apex.
+func relativeDnsHostname(hostname string, zone string) string {
+	hostname = normalizeDnsHostname(hostname, zone)
+	zone = strings.TrimSuffix(strings.ToLower(zone), ".")
+	if hostname == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(hostname, "."+zone)
+}
@@ -0,0 +1,472 @@
+package netlify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDnsRecord_import(t *testing.T) {
+	resourceName := "netlify_dns_record.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsRecordConfig, domain),
+			},
+
+			{
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStateVerify:  true,
+				ImportStateIdFunc:  testAccDnsRecordImportID(resourceName, "record"),
+				ImportStatePersist: true,
+			},
+
+			// The imported record must not be replaced
+			{
+				Config:   fmt.Sprintf(testAccDnsRecordConfig, domain),
+				PlanOnly: true,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccDnsRecordImportID(resourceName, "hostname"),
+			},
+		},
+	})
+}
+
+// Builds an import ID of the form zone_id/record_id or zone_id/hostname/type.
+func testAccDnsRecordImportID(n string, form string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not Found: %s", n)
+		}
+
+		if form == "hostname" {
+			return fmt.Sprintf("%s/%s/%s", rs.Primary.Attributes["zone_id"], rs.Primary.Attributes["hostname"], rs.Primary.Attributes["type"]), nil
+		}
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["zone_id"], rs.Primary.ID), nil
+	}
+}
+
+// Netlify can't update records, so a new value replaces the record. With
+// create_before_destroy the new record exists before the old one is removed.
+func TestAccDnsRecord_changeValue(t *testing.T) {
+	resourceName := "netlify_dns_record.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	var recordID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsRecordConfig_createBeforeDestroy, domain, "10.0.0.1"),
+				Check: func(s *terraform.State) error {
+					recordID = s.RootModule().Resources[resourceName].Primary.ID
+					return nil
+				},
+			},
+
+			{
+				Config: fmt.Sprintf(testAccDnsRecordConfig_createBeforeDestroy, domain, "10.0.0.2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "value", "10.0.0.2"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[resourceName].Primary.ID == recordID {
+							return fmt.Errorf("expected the record to be replaced")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// wait_for_propagation only applies while creating the record, so setting it
+// later must not replace the record.
+func TestAccDnsRecord_waitForPropagationChanged(t *testing.T) {
+	resourceName := "netlify_dns_record.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	var recordID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsRecordConfig, domain),
+				Check: func(s *terraform.State) error {
+					recordID = s.RootModule().Resources[resourceName].Primary.ID
+					return nil
+				},
+			},
+
+			{
+				Config: fmt.Sprintf(testAccDnsRecordConfig_waitForPropagation, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "wait_for_propagation", "true"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[resourceName].Primary.ID != recordID {
+							return fmt.Errorf("expected the record to be kept")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsRecord_siteID(t *testing.T) {
+	resourceName := "netlify_dns_record.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsRecordConfig_siteID, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "site_id", "netlify_site.test", "id"),
+					resource.TestMatchResourceAttr(resourceName, "value", regexp.MustCompile(`\.netlify\.app$`)),
+				),
+			},
+
+			{
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStateVerify:  true,
+				ImportStateIdFunc:  testAccDnsRecordImportID(resourceName, "record"),
+				ImportStatePersist: true,
+			},
+
+			// The imported record must not be replaced
+			{
+				Config:   fmt.Sprintf(testAccDnsRecordConfig_siteID, domain),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestResourceDnsRecord_importSiteID(t *testing.T) {
+	var record string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/dns_zones/zone/dns_records/record":
+			w.Write([]byte(record))
+		case "/api/v1/sites/mysite.netlify.app":
+			w.Write([]byte(`{"id": "site", "default_domain": "mysite.netlify.app"}`))
+		case "/api/v1/sites/www.example.com":
+			w.Write([]byte(`{"id": "site", "default_domain": "mysite.netlify.app"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		record   string
+		expected string
+	}{
+		{`{"id": "record", "type": "CNAME", "value": "mysite.netlify.app."}`, "site"},
+		{`{"id": "record", "type": "ALIAS", "value": "MySite.netlify.app"}`, "site"},
+		// A site's custom domain isn't its subdomain
+		{`{"id": "record", "type": "CNAME", "value": "www.example.com"}`, ""},
+		{`{"id": "record", "type": "CNAME", "value": "other.example.com"}`, ""},
+		{`{"id": "record", "type": "A", "value": "10.0.0.1"}`, ""},
+	}
+
+	for _, c := range cases {
+		record = c.record
+		actual, err := resourceDnsRecord_importSiteID(client.(*Meta), "zone", "record")
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.record, err)
+		}
+		if actual != c.expected {
+			t.Errorf("%s: expected site %q, got %q", c.record, c.expected, actual)
+		}
+	}
+}
+
+func TestResourceDnsRecordRead_siteRenamed(t *testing.T) {
+	domain := "mysite.netlify.app"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/dns_zones/zone":
+			w.Write([]byte(`{"id": "zone", "name": "example.com"}`))
+		case "/api/v1/dns_zones/zone/dns_records/record":
+			w.Write([]byte(`{"id": "record", "hostname": "www.example.com", "type": "CNAME", "value": "mysite.netlify.app"}`))
+		case "/api/v1/sites/site":
+			fmt.Fprintf(w, `{"id": "site", "default_domain": %q}`, domain)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		domain   string
+		expected string
+	}{
+		{"mysite.netlify.app", "site"},
+		{"renamed.netlify.app", ""},
+	}
+
+	for _, c := range cases {
+		domain = c.domain
+		d := resourceDnsRecord().Data(&terraform.InstanceState{
+			ID: "record",
+			Attributes: map[string]string{
+				"zone_id":  "zone",
+				"hostname": "www",
+				"type":     "CNAME",
+				"value":    "mysite.netlify.app",
+				"site_id":  "site",
+			},
+		})
+		if err := resourceDnsRecordRead(d, client); err != nil {
+			t.Fatalf("%s: err: %s", c.domain, err)
+		}
+		if actual := d.Get("site_id").(string); actual != c.expected {
+			t.Errorf("%s: expected site_id %q, got %q", c.domain, c.expected, actual)
+		}
+	}
+}
+
+func TestResourceDnsRecord_checkSiteID(t *testing.T) {
+	cases := []struct {
+		recordType string
+		siteID     string
+		valid      bool
+	}{
+		{"CNAME", "site", true},
+		{"alias", "site", true},
+		{"A", "site", false},
+		{"TXT", "site", false},
+		{"A", "", true},
+	}
+
+	for _, c := range cases {
+		err := resourceDnsRecord_checkSiteID(c.recordType, c.siteID)
+		if c.valid && err != nil {
+			t.Errorf("expected %s with site_id %q to be valid, got %s", c.recordType, c.siteID, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("expected %s with site_id %q to be rejected", c.recordType, c.siteID)
+		}
+	}
+}
+
+func TestNormalizeDnsHostname(t *testing.T) {
+	cases := []struct {
+		hostname string
+		expected string
+	}{
+		// apex
+		{"", "example.com"},
+		{"@", "example.com"},
+		{"example.com", "example.com"},
+		{"example.com.", "example.com"},
+
+		// subdomain
+		{"www", "www.example.com"},
+		{"www.example.com", "www.example.com"},
+		{"WWW.Example.com.", "www.example.com"},
+		{"api.staging", "api.staging.example.com"},
+
+		// wildcard
+		{"*", "*.example.com"},
+		{"*.example.com", "*.example.com"},
+		{"*.staging", "*.staging.example.com"},
+	}
+
+	for _, tc := range cases {
+		if actual := normalizeDnsHostname(tc.hostname, "example.com"); actual != tc.expected {
+			t.Errorf("normalizeDnsHostname(%q): expected %q, got %q", tc.hostname, tc.expected, actual)
+		}
+	}
+}
+
+func TestRelativeDnsHostname(t *testing.T) {
+	cases := []struct {
+		hostname string
+		expected string
+	}{
+		{"example.com", "@"},
+		{"www.example.com", "www"},
+		{"WWW.Example.com.", "www"},
+		{"api.staging.example.com", "api.staging"},
+		{"*.example.com", "*"},
+		{"www", "www"},
+	}
+
+	for _, tc := range cases {
+		if actual := relativeDnsHostname(tc.hostname, "example.com"); actual != tc.expected {
+			t.Errorf("relativeDnsHostname(%q): expected %q, got %q", tc.hostname, tc.expected, actual)
+		}
+	}
+}
+
+func TestResourceDnsRecord_hostnameDiffSuppress(t *testing.T) {
+	d := resourceDnsRecord().TestResourceData()
+	if resourceDnsRecord_hostnameDiffSuppress("hostname", "www.example.com", "www", d) {
+		t.Errorf("expected no diff to be suppressed before the zone is known")
+	}
+
+	d.Set("zone_name", "example.com")
+	cases := []struct {
+		old      string
+		new      string
+		expected bool
+	}{
+		{"www.example.com", "www", true},
+		{"www", "www.example.com.", true},
+		{"example.com", "@", true},
+		{"www.example.com", "api", false},
+		{"", "www", false},
+	}
+
+	for _, tc := range cases {
+		if actual := resourceDnsRecord_hostnameDiffSuppress("hostname", tc.old, tc.new, d); actual != tc.expected {
+			t.Errorf("%q -> %q: expected %t, got %t", tc.old, tc.new, tc.expected, actual)
+		}
+	}
+}
+
+func TestResourceDnsRecord_waitForPropagation(t *testing.T) {
+	calls := 0
+	err := resourceDnsRecord_waitForPropagation(time.Minute, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 polls, got %d", calls)
+	}
+
+	err = resourceDnsRecord_waitForPropagation(time.Second, func() (bool, error) {
+		return false, nil
+	})
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+
+	failure := errors.New("lookup failed")
+	err = resourceDnsRecord_waitForPropagation(time.Minute, func() (bool, error) {
+		return false, failure
+	})
+	if err != failure {
+		t.Fatalf("expected the lookup error, got %v", err)
+	}
+}
+
+var testAccDnsRecordConfig = `
+resource "netlify_site" "test" {}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%s"
+}
+
+resource "netlify_dns_record" "test" {
+	zone_id = netlify_dns_zone.test.id
+	hostname = "www"
+	type = "A"
+	value = "10.0.0.1"
+}
+`
+
+var testAccDnsRecordConfig_waitForPropagation = `
+resource "netlify_site" "test" {}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%s"
+}
+
+resource "netlify_dns_record" "test" {
+	zone_id = netlify_dns_zone.test.id
+	hostname = "www"
+	type = "A"
+	value = "10.0.0.1"
+	wait_for_propagation = true
+}
+`
+
+var testAccDnsRecordConfig_createBeforeDestroy = `
+resource "netlify_site" "test" {}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%s"
+}
+
+resource "netlify_dns_record" "test" {
+	zone_id = netlify_dns_zone.test.id
+	hostname = "www"
+	type = "A"
+	value = "%s"
+
+	lifecycle {
+		create_before_destroy = true
+	}
+}
+`
+
+var testAccDnsRecordConfig_siteID = `
+resource "netlify_site" "test" {}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%s"
+}
+
+resource "netlify_dns_record" "test" {
+	zone_id = netlify_dns_zone.test.id
+	hostname = "www"
+	type = "CNAME"
+	site_id = netlify_site.test.id
+}
+`
@@ -0,0 +1,239 @@
+package netlify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Manages a set of records in a DNS zone as one resource. Records can't be
+// updated in place, so changing any attribute of a record recreates just that
+// record. The IDs of the created records are tracked in record_ids, keyed by
+// the hash of the record block they were created from.
+func resourceDnsRecords() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDnsRecordsCreate,
+		Read:   resourceDnsRecordsRead,
+		Update: resourceDnsRecordsUpdate,
+		Delete: resourceDnsRecordsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"record": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"hostname": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The hostname of the record, either fully qualified or relative to the zone (`@` for the zone apex).",
+						},
+
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"value": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"ttl": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+
+						"priority": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "The priority of `MX` and `SRV` records.",
+						},
+					},
+				},
+			},
+
+			"record_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceDnsRecordsCreate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	d.SetId(d.Get("zone_id").(string))
+
+	records := d.Get("record").(*schema.Set)
+	ids, err := resourceDnsRecords_create(d, meta, records, map[string]interface{}{}, records.List())
+	d.Set("record_ids", ids)
+	if err != nil {
+		return err
+	}
+
+	return resourceDnsRecordsRead(d, metaRaw)
+}
+
+func resourceDnsRecordsRead(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	params := operations.NewGetDNSRecordsParams()
+	params.ZoneID = d.Id()
+	resp, err := meta.Operations.GetDNSRecords(params, meta.AuthInfo)
+	if err != nil {
+		// If it is a 404 the whole zone was removed remotely
+		if v, ok := err.(*operations.GetDNSRecordsDefault); ok && v.Code() == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	existing := map[string]*models.DNSRecord{}
+	for _, record := range resp.Payload {
+		existing[record.ID] = record
+	}
+
+	// Keep the records as configured as long as they still exist with the
+	// same value, so that only the records that drifted get recreated.
+	records := d.Get("record").(*schema.Set)
+	ids := d.Get("record_ids").(map[string]interface{})
+	kept := schema.NewSet(records.F, nil)
+	keptIDs := map[string]interface{}{}
+	for _, r := range records.List() {
+		key := resourceDnsRecords_key(records, r)
+		id, ok := ids[key].(string)
+		if !ok {
+			continue
+		}
+
+		record, ok := existing[id]
+		if !ok || !resourceDnsRecords_sameValue(record.Value, r.(map[string]interface{})["value"].(string)) {
+			continue
+		}
+
+		kept.Add(r)
+		keptIDs[key] = id
+	}
+
+	d.Set("zone_id", d.Id())
+	d.Set("record", kept)
+	d.Set("record_ids", keptIDs)
+
+	return nil
+}
+
+func resourceDnsRecordsUpdate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	o, n := d.GetChange("record")
+	old, new := o.(*schema.Set), n.(*schema.Set)
+
+	ids := map[string]interface{}{}
+	for k, v := range d.Get("record_ids").(map[string]interface{}) {
+		ids[k] = v
+	}
+
+	for _, r := range old.Difference(new).List() {
+		key := resourceDnsRecords_key(old, r)
+		if id, ok := ids[key].(string); ok {
+			if err := resourceDnsRecords_delete(meta, d.Id(), id); err != nil {
+				d.Set("record_ids", ids)
+				return err
+			}
+		}
+		delete(ids, key)
+	}
+
+	ids, err := resourceDnsRecords_create(d, meta, new, ids, new.Difference(old).List())
+	d.Set("record_ids", ids)
+	if err != nil {
+		return err
+	}
+
+	return resourceDnsRecordsRead(d, metaRaw)
+}
+
+func resourceDnsRecordsDelete(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	for _, id := range d.Get("record_ids").(map[string]interface{}) {
+		if err := resourceDnsRecords_delete(meta, d.Id(), id.(string)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Creates the given records, adding their IDs to ids. ids is returned even on
+// error so the records created so far are still tracked.
+func resourceDnsRecords_create(d *schema.ResourceData, meta *Meta, set *schema.Set, ids map[string]interface{}, records []interface{}) (map[string]interface{}, error) {
+	if len(records) == 0 {
+		return ids, nil
+	}
+
+	zone, err := resourceDnsRecord_zoneName(meta, d.Id())
+	if err != nil {
+		return ids, err
+	}
+
+	for _, r := range records {
+		record := r.(map[string]interface{})
+		params := operations.NewCreateDNSRecordParams()
+		params.ZoneID = d.Id()
+		params.DNSRecord = &models.DNSRecordCreate{
+			Hostname: normalizeDnsHostname(record["hostname"].(string), zone),
+			Type:     record["type"].(string),
+			Value:    record["value"].(string),
+			TTL:      int64(record["ttl"].(int)),
+			Priority: int64(record["priority"].(int)),
+		}
+
+		resp, err := meta.Operations.CreateDNSRecord(params, meta.AuthInfo)
+		if err != nil {
+			return ids, fmt.Errorf("Error creating DNS record %s: %s", resourceDnsRecords_describe(record), err)
+		}
+		ids[resourceDnsRecords_key(set, r)] = resp.Payload.ID
+	}
+
+	return ids, nil
+}
+
+// Deletes a record, ignoring records that are already gone.
+func resourceDnsRecords_delete(meta *Meta, zoneID string, id string) error {
+	params := operations.NewDeleteDNSRecordParams()
+	params.ZoneID = zoneID
+	params.DNSRecordID = id
+	_, err := meta.Operations.DeleteDNSRecord(params, meta.AuthInfo)
+	if v, ok := err.(*operations.DeleteDNSRecordDefault); ok && v.Code() == 404 {
+		return nil
+	}
+	return err
+}
+
+// Returns the record_ids key of a record block.
+func resourceDnsRecords_key(set *schema.Set, record interface{}) string {
+	return strconv.Itoa(set.F(record))
+}
+
+// Compares record values the way DNS does, ignoring case and trailing dots.
+func resourceDnsRecords_sameValue(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// Returns a readable summary of a record block for error messages.
+func resourceDnsRecords_describe(record map[string]interface{}) string {
+	return strings.Join([]string{record["hostname"].(string), record["type"].(string), record["value"].(string)}, " ")
+}
@@ -0,0 +1,126 @@
+package netlify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestAccDnsRecords_basic(t *testing.T) {
+	resourceName := "netlify_dns_records.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsRecordsConfig, domain, `
+	record {
+		hostname = "www"
+		type = "A"
+		value = "10.0.0.1"
+	}
+
+	record {
+		hostname = "@"
+		type = "MX"
+		value = "mx1.example.net"
+		priority = 10
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "record.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "record_ids.%", "2"),
+					testAccCheckDnsRecordsExist(resourceName),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccDnsRecordsConfig, domain, `
+	record {
+		hostname = "www"
+		type = "A"
+		value = "10.0.0.2"
+	}
+
+	record {
+		hostname = "api"
+		type = "CNAME"
+		value = "api.example.net"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "record.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "record_ids.%", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "record.*", map[string]string{
+						"hostname": "www",
+						"value":    "10.0.0.2",
+					}),
+					testAccCheckDnsRecordsExist(resourceName),
+				),
+			},
+		},
+	})
+}
+
+// Checks that every record tracked in record_ids exists, and that no others
+// were left behind.
+func testAccCheckDnsRecordsExist(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetDNSRecordsParams()
+		params.ZoneID = rs.Primary.ID
+		resp, err := meta.Operations.GetDNSRecords(params, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+
+		existing := map[string]bool{}
+		for _, record := range resp.Payload {
+			if !record.Managed {
+				existing[record.ID] = true
+			}
+		}
+
+		tracked := 0
+		for k, id := range rs.Primary.Attributes {
+			if k == "record_ids.%" || !strings.HasPrefix(k, "record_ids.") {
+				continue
+			}
+			if !existing[id] {
+				return fmt.Errorf("DNS record %s does not exist", id)
+			}
+			tracked++
+		}
+
+		if tracked != len(existing) {
+			return fmt.Errorf("Expected %d DNS records, found %d", tracked, len(existing))
+		}
+		return nil
+	}
+}
+
+var testAccDnsRecordsConfig = `
+resource "netlify_site" "test" {}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%s"
+}
+
+resource "netlify_dns_records" "test" {
+	zone_id = netlify_dns_zone.test.id
+%s
+}
+`
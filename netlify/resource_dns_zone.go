@@ -1,6 +1,9 @@
 package netlify
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
@@ -10,16 +13,17 @@ func resourceDnsZone() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDnsZoneCreate,
 		Read:   resourceDnsZoneRead,
+		Update: resourceDnsZoneUpdate,
 		Delete: resourceDnsZoneDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceDnsZoneImport,
 		},
 
 		Schema: map[string]*schema.Schema{
 			"site_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the site the zone is linked to. Changing it deletes the records Netlify manages for the old site and links the zone to the new one, whose `custom_domain` must be within the zone.",
 			},
 
 			"name": {
@@ -32,6 +36,28 @@ func resourceDnsZone() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to delete the zone even if it still has records other than the ones Netlify manages for linked sites.",
+			},
+
+			"records_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of records in the zone, including the ones Netlify manages for linked sites.",
+			},
+
+			"name_servers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The name servers of the zone, to delegate the domain to at its registrar.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
@@ -44,7 +70,7 @@ func resourceDnsZoneCreate(d *schema.ResourceData, metaRaw interface{}) error {
 	}
 
 	meta := metaRaw.(*Meta)
-	resp, err := meta.Netlify.Operations.CreateDNSZone(params, meta.AuthInfo)
+	resp, err := meta.Operations.CreateDNSZone(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
@@ -57,7 +83,7 @@ func resourceDnsZoneRead(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
 	params := operations.NewGetDNSZoneParams()
 	params.ZoneID = d.Id()
-	resp, err := meta.Netlify.Operations.GetDNSZone(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetDNSZone(params, meta.AuthInfo)
 	if err != nil {
 		// If it is a 404 it was removed remotely
 		if v, ok := err.(*operations.GetDNSZoneDefault); ok && v.Code() == 404 {
@@ -68,18 +94,127 @@ func resourceDnsZoneRead(d *schema.ResourceData, metaRaw interface{}) error {
 		return err
 	}
 
+	// The zone's site_id changes when another site is linked to the zone
+	// outside of Terraform, so it is read back rather than kept as configured.
 	zone := resp.Payload
 	d.Set("site_id", zone.SiteID)
 	d.Set("name", zone.Name)
 	d.Set("domain", zone.Domain)
+	d.Set("name_servers", zone.DNSServers)
+
+	records := operations.NewGetDNSRecordsParams()
+	records.ZoneID = d.Id()
+	recordsResp, err := meta.Operations.GetDNSRecords(records, meta.AuthInfo)
+	if err != nil {
+		return err
+	}
+	d.Set("records_count", len(recordsResp.Payload))
 
 	return nil
 }
 
+func resourceDnsZoneUpdate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+
+	// There is no endpoint to update a zone directly, so re-associating it
+	// with another site unlinks the old site and then goes through
+	// configuring DNS for the new site, which links the site's domain to its
+	// existing Netlify DNS zone. The zone and its other records are left
+	// untouched.
+	if d.HasChange("site_id") {
+		old, _ := d.GetChange("site_id")
+		if err := resourceDnsZone_unlinkSite(meta, d.Id(), old.(string)); err != nil {
+			return err
+		}
+
+		params := operations.NewConfigureDNSForSiteParams()
+		params.SiteID = d.Get("site_id").(string)
+		_, err := meta.Operations.ConfigureDNSForSite(params, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+
+		// Netlify only links the zone if it matches the site's custom domain,
+		// so verify the association actually moved.
+		get := operations.NewGetDNSZoneParams()
+		get.ZoneID = d.Id()
+		resp, err := meta.Operations.GetDNSZone(get, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+		if resp.Payload.SiteID != params.SiteID {
+			return fmt.Errorf("DNS zone %s was not linked to site %s; the site's custom_domain must match the zone name", d.Id(), params.SiteID)
+		}
+	}
+
+	return resourceDnsZoneRead(d, metaRaw)
+}
+
 func resourceDnsZoneDelete(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
+	if !d.Get("force_destroy").(bool) {
+		records := operations.NewGetDNSRecordsParams()
+		records.ZoneID = d.Id()
+		resp, err := meta.Operations.GetDNSRecords(records, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+
+		if custom := resourceDnsZone_customRecords(resp.Payload); len(custom) > 0 {
+			return fmt.Errorf("DNS zone %s still has %d records that Netlify doesn't manage (%s); delete them first or set force_destroy = true", d.Id(), len(custom), strings.Join(custom, ", "))
+		}
+	}
+
 	params := operations.NewDeleteDNSZoneParams()
 	params.ZoneID = d.Id()
-	_, err := meta.Netlify.Operations.DeleteDNSZone(params, meta.AuthInfo)
+	_, err := meta.Operations.DeleteDNSZone(params, meta.AuthInfo)
 	return err
 }
+
+// force_destroy only exists in the configuration, so it is set to its default
+// to keep imported zones from planning a change.
+func resourceDnsZoneImport(d *schema.ResourceData, metaRaw interface{}) ([]*schema.ResourceData, error) {
+	d.Set("force_destroy", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// Deletes the records Netlify manages in the zone for the site, so the zone
+// stops pointing the site's domain at it. The API has no endpoint to unlink a
+// site from a zone otherwise.
+func resourceDnsZone_unlinkSite(meta *Meta, zoneID string, siteID string) error {
+	if siteID == "" {
+		return nil
+	}
+
+	records := operations.NewGetDNSRecordsParams()
+	records.ZoneID = zoneID
+	resp, err := meta.Operations.GetDNSRecords(records, meta.AuthInfo)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range resp.Payload {
+		if !record.Managed || record.SiteID != siteID {
+			continue
+		}
+		params := operations.NewDeleteDNSRecordParams()
+		params.ZoneID = zoneID
+		params.DNSRecordID = record.ID
+		if _, err := meta.Operations.DeleteDNSRecord(params, meta.AuthInfo); err != nil {
+			return fmt.Errorf("Error unlinking site %s from DNS zone %s: %s", siteID, zoneID, err)
+		}
+	}
+	return nil
+}
+
+// Describes the records of a zone that Netlify doesn't manage itself.
+func resourceDnsZone_customRecords(records []*models.DNSRecord) []string {
+	var custom []string
+	for _, record := range records {
+		if record.Managed {
+			continue
+		}
+		custom = append(custom, fmt.Sprintf("%s %s", record.Hostname, record.Type))
+	}
+	return custom
+}
@@ -0,0 +1,290 @@
+package netlify
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestAccDnsZone_updateSite(t *testing.T) {
+	var zone models.DNSZone
+	var first, second models.Site
+	resourceName := "netlify_dns_zone.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsZoneConfig_site, domain, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists("netlify_site.first", &first),
+					testAccCheckDnsZoneExists(resourceName, &zone),
+					testAccAssert("linked to first site", func() bool {
+						return zone.SiteID == first.ID
+					}),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccDnsZoneConfig_site, domain, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists("netlify_site.second", &second),
+					testAccCheckDnsZoneExists(resourceName, &zone),
+					testAccAssert("linked to second site", func() bool {
+						return zone.SiteID == second.ID
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsZone_readSite(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_dns_zone.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDnsZoneConfig_site, domain, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists("netlify_site.first", &site),
+					resource.TestCheckResourceAttrPair(resourceName, "site_id", "netlify_site.first", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "records_count"),
+				),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceDnsZoneRead(t *testing.T) {
+	ops := &testOperations{
+		getDNSZone: func(params *operations.GetDNSZoneParams) (*operations.GetDNSZoneOK, error) {
+			return &operations.GetDNSZoneOK{Payload: &models.DNSZone{ID: params.ZoneID, SiteID: "linked", Name: "example.com", DNSServers: []string{"dns1.p01.nsone.net"}}}, nil
+		},
+		getDNSRecords: func(params *operations.GetDNSRecordsParams) (*operations.GetDNSRecordsOK, error) {
+			return &operations.GetDNSRecordsOK{Payload: []*models.DNSRecord{{ID: "a"}, {ID: "b"}}}, nil
+		},
+	}
+
+	d := resourceDnsZone().TestResourceData()
+	d.SetId("zone")
+	d.Set("site_id", "configured")
+	if err := resourceDnsZoneRead(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual := d.Get("site_id").(string); actual != "linked" {
+		t.Errorf("expected the linked site to be read back, got %q", actual)
+	}
+	if actual := d.Get("records_count").(int); actual != 2 {
+		t.Errorf("expected 2 records, got %d", actual)
+	}
+	if actual := d.Get("name_servers").([]interface{}); len(actual) != 1 || actual[0] != "dns1.p01.nsone.net" {
+		t.Errorf("expected the zone's name servers, got %v", actual)
+	}
+}
+
+func TestResourceDnsZoneRead_notFound(t *testing.T) {
+	ops := &testOperations{
+		getDNSZone: func(params *operations.GetDNSZoneParams) (*operations.GetDNSZoneOK, error) {
+			return nil, operations.NewGetDNSZoneDefault(404)
+		},
+	}
+
+	d := resourceDnsZone().TestResourceData()
+	d.SetId("zone")
+	if err := resourceDnsZoneRead(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected a removed zone to be dropped from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceDnsZoneUpdate_site(t *testing.T) {
+	linked := "old-site"
+	var calls []string
+	ops := &testOperations{
+		getDNSRecords: func(params *operations.GetDNSRecordsParams) (*operations.GetDNSRecordsOK, error) {
+			return &operations.GetDNSRecordsOK{Payload: []*models.DNSRecord{
+				{ID: "old-managed", Managed: true, SiteID: "old-site"},
+				{ID: "other-managed", Managed: true, SiteID: "other-site"},
+				{ID: "custom", SiteID: "old-site"},
+			}}, nil
+		},
+		deleteDNSRecord: func(params *operations.DeleteDNSRecordParams) (*operations.DeleteDNSRecordNoContent, error) {
+			calls = append(calls, "delete "+params.DNSRecordID)
+			return operations.NewDeleteDNSRecordNoContent(), nil
+		},
+		configureDNSForSite: func(params *operations.ConfigureDNSForSiteParams) (*operations.ConfigureDNSForSiteOK, error) {
+			calls = append(calls, "configure "+params.SiteID)
+			linked = params.SiteID
+			return &operations.ConfigureDNSForSiteOK{}, nil
+		},
+		getDNSZone: func(params *operations.GetDNSZoneParams) (*operations.GetDNSZoneOK, error) {
+			return &operations.GetDNSZoneOK{Payload: &models.DNSZone{ID: params.ZoneID, SiteID: linked, Name: "example.com"}}, nil
+		},
+	}
+
+	d := testResourceDataUpdate(t, resourceDnsZone(), &terraform.InstanceState{
+		ID: "zone",
+		Attributes: map[string]string{
+			"site_id":       "old-site",
+			"name":          "example.com",
+			"force_destroy": "false",
+		},
+	}, map[string]interface{}{
+		"site_id": "new-site",
+		"name":    "example.com",
+	})
+	if err := resourceDnsZoneUpdate(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"delete old-managed", "configure new-site"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected the old site to be unlinked before linking the new one, got %v", calls)
+	}
+	if actual := d.Get("site_id").(string); actual != "new-site" {
+		t.Errorf("expected the zone to be linked to new-site, got %q", actual)
+	}
+}
+
+func TestResourceDnsZoneDelete_forceDestroy(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		deleted := false
+		ops := &testOperations{
+			getDNSRecords: func(params *operations.GetDNSRecordsParams) (*operations.GetDNSRecordsOK, error) {
+				return &operations.GetDNSRecordsOK{Payload: []*models.DNSRecord{
+					{Hostname: "example.com", Type: "NETLIFY", Managed: true},
+					{Hostname: "www.example.com", Type: "CNAME"},
+				}}, nil
+			},
+			deleteDNSZone: func(params *operations.DeleteDNSZoneParams) (*operations.DeleteDNSZoneNoContent, error) {
+				deleted = true
+				return &operations.DeleteDNSZoneNoContent{}, nil
+			},
+		}
+
+		d := resourceDnsZone().TestResourceData()
+		d.SetId("zone")
+		d.Set("force_destroy", force)
+		err := resourceDnsZoneDelete(d, testMeta(ops))
+		if force && (err != nil || !deleted) {
+			t.Errorf("expected force_destroy to delete the zone, got %v", err)
+		}
+		if !force && (err == nil || !strings.Contains(err.Error(), "www.example.com CNAME") || deleted) {
+			t.Errorf("expected the custom record to block deleting the zone, got %v", err)
+		}
+	}
+}
+
+func TestResourceDnsZoneDelete_onlyManagedRecords(t *testing.T) {
+	deleted := false
+	ops := &testOperations{
+		getDNSRecords: func(params *operations.GetDNSRecordsParams) (*operations.GetDNSRecordsOK, error) {
+			return &operations.GetDNSRecordsOK{Payload: []*models.DNSRecord{
+				{Hostname: "example.com", Type: "NETLIFY", Managed: true},
+			}}, nil
+		},
+		deleteDNSZone: func(params *operations.DeleteDNSZoneParams) (*operations.DeleteDNSZoneNoContent, error) {
+			deleted = true
+			return &operations.DeleteDNSZoneNoContent{}, nil
+		},
+	}
+
+	d := resourceDnsZone().TestResourceData()
+	d.SetId("zone")
+	if err := resourceDnsZoneDelete(d, testMeta(ops)); err != nil || !deleted {
+		t.Errorf("expected a zone with only managed records to be deleted, got %v", err)
+	}
+}
+
+func testAccCheckDnsZoneExists(n string, zone *models.DNSZone) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No zone ID is set")
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetDNSZoneParams()
+		params.ZoneID = rs.Primary.ID
+		resp, err := meta.Operations.GetDNSZone(params, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+
+		*zone = *resp.Payload
+		return nil
+	}
+}
+
+func testAccCheckDnsZoneDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "netlify_dns_zone" {
+			continue
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetDNSZoneParams()
+		params.ZoneID = rs.Primary.ID
+		resp, err := meta.Operations.GetDNSZone(params, meta.AuthInfo)
+		if err == nil && resp.Payload != nil {
+			return fmt.Errorf("DNS zone still exists: %s", rs.Primary.ID)
+		}
+
+		if err != nil {
+			if v, ok := err.(*operations.GetDNSZoneDefault); ok && v.Code() == 404 {
+				return nil
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+var testAccDnsZoneConfig_site = `
+locals {
+	domain = "%s"
+}
+
+resource "netlify_site" "first" {
+	custom_domain = local.domain
+}
+
+resource "netlify_site" "second" {
+	custom_domain = "www.${local.domain}"
+}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.%s.id
+	name = local.domain
+}
+`
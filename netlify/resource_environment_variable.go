@@ -1,6 +1,7 @@
 package netlify
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -16,13 +17,14 @@ func resourceEnvVar() *schema.Resource {
 		Update: resourceEnvVarUpdate,
 		Delete: resourceEnvVarDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceEnvVarImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"account_id": {
 				Type:        schema.TypeString,
-				Description: "The account ID / slug to create the environment variable for.",
-				Required:    true,
+				Description: "The account ID / slug to create the environment variable for. It is used as given, so variables of sites in different teams can be managed side by side; if unset, the provider's `default_account_slug` is used.",
+				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 			},
 
@@ -55,12 +57,17 @@ func resourceEnvVar() *schema.Resource {
 func resourceEnvVarCreate(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
 
-	// initialize creation parameters with default account ID, or supplied.
+	// initialize creation parameters with the supplied account ID, or the
+	// provider's default account slug.
 	params := operations.NewCreateEnvVarsParams()
 	key := d.Get("key").(string)
 	site_id := d.Get("site_id").(string)
-	params.AccountID = d.Get("account_id").(string)
+	params.AccountID = meta.accountSlug(d.Get("account_id").(string))
 	params.SiteID = &site_id
+	if params.AccountID == "" {
+		return fmt.Errorf("account_id must be set when the provider has no default_account_slug")
+	}
+	d.Set("account_id", params.AccountID)
 
 	// build env vars create object
 	env_vars := models.CreateEnvVarsParamsBodyItems{}
@@ -92,7 +99,7 @@ func resourceEnvVarCreate(d *schema.ResourceData, metaRaw interface{}) error {
 	params.EnvVars = []*models.CreateEnvVarsParamsBodyItems{&env_vars}
 
 	// perform the operation
-	_, err := meta.Netlify.Operations.CreateEnvVars(params, meta.AuthInfo)
+	_, err := meta.Operations.CreateEnvVars(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
@@ -111,18 +118,20 @@ func resourceEnvVarRead(d *schema.ResourceData, metaRaw interface{}) error {
 	params.SiteID = site_id
 	params.Key = key
 
-	resp, err := meta.Netlify.Operations.GetEnvVar(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
 	if err != nil {
 		// If it is a 404, it was removed remotely
 		if v, ok := err.(*operations.GetEnvVarDefault); ok && v.Code() == 404 {
 			d.SetId("")
 			return nil
 		}
+
+		return err
 	}
 	envVar := resp.Payload
 	d.Set("key", envVar.Key)
 	d.Set("scopes", envVar.Scopes)
-	return err
+	return nil
 }
 
 func resourceEnvVarUpdate(d *schema.ResourceData, metaRaw interface{}) error {
@@ -158,7 +167,7 @@ func resourceEnvVarUpdate(d *schema.ResourceData, metaRaw interface{}) error {
 	params_get.AccountID = account_id
 	params_get.SiteID = site_id
 	params_get.Key = key
-	resp_get, err_get := meta.Netlify.Operations.GetEnvVar(params_get, meta.AuthInfo)
+	resp_get, err_get := meta.Operations.GetEnvVar(params_get, meta.AuthInfo)
 	if err_get != nil {
 		return err_get
 	}
@@ -166,7 +175,7 @@ func resourceEnvVarUpdate(d *schema.ResourceData, metaRaw interface{}) error {
 	params.EnvVar = &env_vars
 
 	// perform the operation
-	resp, err := meta.Netlify.Operations.UpdateEnvVar(params, meta.AuthInfo)
+	resp, err := meta.Operations.UpdateEnvVar(params, meta.AuthInfo)
 	if err != nil {
 		return err
 	}
@@ -185,10 +194,44 @@ func resourceEnvVarDelete(d *schema.ResourceData, metaRaw interface{}) error {
 	site_id := d.Get("site_id").(string)
 	params.SiteID = &site_id
 	params.Key = d.Get("key").(string)
-	_, err := meta.Netlify.Operations.DeleteEnvVar(params, meta.AuthInfo)
+	_, err := meta.Operations.DeleteEnvVar(params, meta.AuthInfo)
+	if err != nil {
+		// If it is a 404 it was already deleted, e.g. along with its site
+		if v, ok := err.(*operations.DeleteEnvVarDefault); ok && v.Code() == 404 {
+			return nil
+		}
+	}
 	return err
 }
 
+// Imports a site level variable by `account_id:site_id:key` and an account
+// level one by `account_id:key`. IDs as found in state are accepted too.
+func resourceEnvVarImport(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) ([]*schema.ResourceData, error) {
+	var accountID, siteID, key string
+	parts := strings.Split(d.Id(), ":")
+	switch {
+	case len(parts) == 2:
+		accountID, key = parts[0], parts[1]
+	case len(parts) == 3:
+		accountID, siteID, key = parts[0], parts[1], parts[2]
+	case strings.Count(d.Id(), "/") >= 2:
+		var site *string
+		accountID, site, key = getEnvVarInfoFromResourceId(d.Id())
+		if site != nil {
+			siteID = *site
+		}
+	}
+	if accountID == "" || key == "" {
+		return nil, fmt.Errorf("Invalid environment variable ID %q; expected account_id:site_id:key or account_id:key", d.Id())
+	}
+
+	d.Set("account_id", accountID)
+	d.Set("site_id", siteID)
+	d.Set("key", key)
+	d.SetId(getResourceIdFromEnvVarInfo(accountID, &siteID, key))
+	return []*schema.ResourceData{d}, nil
+}
+
 func getEnvVarInfoFromResourceId(id string) (account_id string, site_id *string, key string) {
 	split := strings.Split(id, "/")
 	key = split[0]
@@ -1,7 +1,9 @@
 package netlify
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -44,7 +46,7 @@ func TestAccEnvVar_disappears(t *testing.T) {
 		params.AccountID = site.AccountSlug
 		params.SiteID = &site.ID
 		params.Key = envVar.Key
-		_, err := meta.Netlify.Operations.DeleteEnvVar(params, meta.AuthInfo)
+		_, err := meta.Operations.DeleteEnvVar(params, meta.AuthInfo)
 		return err
 	}
 
@@ -91,6 +93,228 @@ func TestAccEnvVar_updatesKey(t *testing.T) {
 	})
 }
 
+// Needs a second team the token is a member of, next to the team the sites
+// are created in by default.
+func TestAccEnvVar_twoAccounts(t *testing.T) {
+	second := os.Getenv("NETLIFY_TEST_SECOND_ACCOUNT_SLUG")
+	if second == "" {
+		t.Skip("NETLIFY_TEST_SECOND_ACCOUNT_SLUG must be set to test environment variables in two accounts")
+	}
+	var first, other models.EnvVar
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteAndEnvVarsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccEnvVarTwoAccountsConfig, second),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEnvVarExists("first", "var1", &first),
+					testAccCheckEnvVarExists("second", "var1", &other),
+					resource.TestCheckResourceAttrPair("netlify_environment_variable.first", "account_id", "netlify_site.first", "account_slug"),
+					resource.TestCheckResourceAttr("netlify_environment_variable.second", "account_id", second),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEnvVar_importSite(t *testing.T) {
+	resourceName := "netlify_environment_variable.var1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteAndEnvVarsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvVarSiteSpecificConfig,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccEnvVarImportID(resourceName),
+			},
+		},
+	})
+}
+
+func TestAccEnvVar_importAccount(t *testing.T) {
+	resourceName := "netlify_environment_variable.test"
+	key := fmt.Sprintf("TEST_%s", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteAndEnvVarsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccEnvVarAccountConfig, key),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccEnvVarImportID(resourceName),
+			},
+		},
+	})
+}
+
+func TestResourceEnvVarImport(t *testing.T) {
+	cases := map[string]struct {
+		accountID, siteID, key string
+	}{
+		"team:site:VAR":  {accountID: "team", siteID: "site", key: "VAR"},
+		"team:VAR":       {accountID: "team", key: "VAR"},
+		"VAR/team/site":  {accountID: "team", siteID: "site", key: "VAR"},
+		"VAR/team//":     {accountID: "team", key: "VAR"},
+		"VAR":            {},
+		"team::site:VAR": {},
+	}
+
+	for id, c := range cases {
+		d := resourceEnvVar().TestResourceData()
+		d.SetId(id)
+		_, err := resourceEnvVarImport(context.Background(), d, testMeta(&testOperations{}))
+		if c.key == "" {
+			if err == nil {
+				t.Errorf("expected %q to be rejected", id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if d.Get("account_id").(string) != c.accountID || d.Get("site_id").(string) != c.siteID || d.Get("key").(string) != c.key {
+			t.Errorf("expected %q to import %+v, got %s/%s/%s", id, c, d.Get("account_id"), d.Get("site_id"), d.Get("key"))
+		}
+		siteID := c.siteID
+		if expected := getResourceIdFromEnvVarInfo(c.accountID, &siteID, c.key); d.Id() != expected {
+			t.Errorf("expected %q to get the ID %q, got %q", id, expected, d.Id())
+		}
+	}
+}
+
+func TestResourceEnvVarCreate_accountID(t *testing.T) {
+	for configured, expected := range map[string]string{"": "default-team", "other-team": "other-team"} {
+		var accountID string
+		ops := &testOperations{
+			createEnvVars: func(params *operations.CreateEnvVarsParams) (*operations.CreateEnvVarsCreated, error) {
+				accountID = params.AccountID
+				return &operations.CreateEnvVarsCreated{}, nil
+			},
+			getEnvVar: func(params *operations.GetEnvVarParams) (*operations.GetEnvVarOK, error) {
+				if params.AccountID != accountID || params.Key != "var1" {
+					t.Errorf("expected var1 to be read from %q, got %s from %q", accountID, params.Key, params.AccountID)
+				}
+				return &operations.GetEnvVarOK{Payload: &models.EnvVar{Key: params.Key, Scopes: []string{"builds"}}}, nil
+			},
+		}
+		meta := testMeta(ops)
+		meta.defaultAccountSlug = "default-team"
+
+		d := resourceEnvVar().TestResourceData()
+		d.Set("account_id", configured)
+		d.Set("key", "var1")
+		if err := resourceEnvVarCreate(d, meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if accountID != expected {
+			t.Errorf("expected account_id %q to create the variable in %q, got %q", configured, expected, accountID)
+		}
+		if actual := d.Get("account_id").(string); actual != expected || d.Id() == "" {
+			t.Errorf("expected the variable to be read back from %q, got %q with ID %q", expected, actual, d.Id())
+		}
+	}
+
+	d := resourceEnvVar().TestResourceData()
+	d.Set("key", "var1")
+	if err := resourceEnvVarCreate(d, testMeta(&testOperations{})); err == nil {
+		t.Errorf("expected an error without account_id or default_account_slug")
+	}
+}
+
+// Sets a variable on the site outside of Terraform, and checks that it is
+// deleted along with the site.
+func TestAccEnvVar_deletedWithSite(t *testing.T) {
+	var site models.Site
+
+	createEnvVar := func(*terraform.State) error {
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewCreateEnvVarsParams()
+		params.AccountID = site.AccountSlug
+		params.SiteID = &site.ID
+		params.EnvVars = []*models.CreateEnvVarsParamsBodyItems{
+			{
+				Key:    "UNMANAGED",
+				Scopes: []string{"builds"},
+				Values: []*models.EnvVarValue{{Context: "all", Value: "value"}},
+			},
+		}
+		_, err := meta.Operations.CreateEnvVars(params, meta.AuthInfo)
+		return err
+	}
+
+	checkDestroy := func(s *terraform.State) error {
+		if err := testAccCheckSiteDestroy(s); err != nil {
+			return err
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetEnvVarsParams()
+		params.AccountID = site.AccountSlug
+		params.SiteID = &site.ID
+		resp, err := meta.Operations.GetEnvVars(params, meta.AuthInfo)
+		if err != nil {
+			if v, ok := err.(*operations.GetEnvVarsDefault); ok && v.Code() == 404 {
+				return nil
+			}
+			return err
+		}
+		if len(resp.Payload) > 0 {
+			return fmt.Errorf("Environment variables of site %s still exist", site.ID)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "netlify_site" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists("netlify_site.test", &site),
+					createEnvVar,
+				),
+			},
+		},
+	})
+}
+
+func TestResourceEnvVarDelete_notFound(t *testing.T) {
+	ops := &testOperations{
+		deleteEnvVar: func(params *operations.DeleteEnvVarParams) (*operations.DeleteEnvVarNoContent, error) {
+			return nil, operations.NewDeleteEnvVarDefault(404)
+		},
+	}
+
+	d := resourceEnvVar().TestResourceData()
+	d.Set("account_id", "team")
+	d.Set("site_id", "site")
+	d.Set("key", "var1")
+	if err := resourceEnvVarDelete(d, testMeta(ops)); err != nil {
+		t.Errorf("expected a variable deleted along with its site to be ignored, got %s", err)
+	}
+}
+
 func testAccCheckEnvVarExists(resource_name string, key string, envvar *models.EnvVar) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["netlify_environment_variable."+resource_name]
@@ -107,7 +331,7 @@ func testAccCheckEnvVarExists(resource_name string, key string, envvar *models.E
 		site_id := rs.Primary.Attributes["site_id"]
 		params.SiteID = &site_id
 		params.Key = key
-		resp, err := meta.Netlify.Operations.GetEnvVar(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
 		if err != nil {
 			return err
 		}
@@ -134,7 +358,7 @@ func testAccCheckSiteAndEnvVarsDestroy(s *terraform.State) error {
 		site_id := rs.Primary.Attributes["site_id"]
 		params.SiteID = &site_id
 		params.Key = rs.Primary.Attributes["key"]
-		resp, err := meta.Netlify.Operations.GetEnvVar(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
 		if err == nil && resp.Payload != nil {
 			return fmt.Errorf("Environment variable still exists: %s", rs.Primary.ID)
 		}
@@ -148,6 +372,30 @@ func testAccCheckSiteAndEnvVarsDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccEnvVarImportID(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("Not Found: %s", resourceName)
+		}
+
+		attributes := rs.Primary.Attributes
+		if attributes["site_id"] == "" {
+			return fmt.Sprintf("%s:%s", attributes["account_id"], attributes["key"]), nil
+		}
+		return fmt.Sprintf("%s:%s:%s", attributes["account_id"], attributes["site_id"], attributes["key"]), nil
+	}
+}
+
+var testAccEnvVarAccountConfig = `
+resource "netlify_site" "test" {}
+
+resource "netlify_environment_variable" "test" {
+	account_id = netlify_site.test.account_slug
+	key	= "%s"
+}
+`
+
 var testAccEnvVarSiteSpecificConfig = `
 resource "netlify_site" "test" {}
 
@@ -179,3 +427,23 @@ resource "netlify_environment_variable" "var2" {
 	key	= "var2"
 }
 `
+
+var testAccEnvVarTwoAccountsConfig = `
+resource "netlify_site" "first" {}
+
+resource "netlify_site" "second" {
+	account_slug = "%s"
+}
+
+resource "netlify_environment_variable" "first" {
+	account_id = netlify_site.first.account_slug
+	site_id = netlify_site.first.id
+	key	= "var1"
+}
+
+resource "netlify_environment_variable" "second" {
+	account_id = netlify_site.second.account_slug
+	site_id = netlify_site.second.id
+	key	= "var1"
+}
+`
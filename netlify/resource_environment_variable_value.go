@@ -2,6 +2,8 @@ package netlify
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/go-openapi/runtime"
 	"github.com/hashicorp/go-cty/cty"
@@ -29,29 +31,17 @@ func resourceEnvVarValue() *schema.Resource {
 			},
 
 			"context": {
-				Type:        schema.TypeString,
-				Description: "The deploy context in which this value will be used. `dev` refers to local development when running `netlify dev`. Enum: [ `dev` `branch-deploy` `deploy-preview` `production`]",
-				Required:    true,
-				ValidateDiagFunc: func(value interface{}, path cty.Path) diag.Diagnostics {
-					for _, v := range []string{"dev", "branch-deploy", "deploy-preview", "production"} {
-						if v == value.(string) {
-							return nil
-						}
-					}
-					return diag.Diagnostics{
-						diag.Diagnostic{
-							Severity: diag.Error,
-							Summary:  "Context level invalid.",
-							Detail:   "Must be one of [`dev` `branch-deploy` `deploy-preview` `production`]",
-						},
-					}
-				},
+				Type:             schema.TypeString,
+				Description:      "The deploy context in which this value will be used. `dev` refers to local development when running `netlify dev`. Enum: [ `dev` `branch-deploy` `deploy-preview` `production`]",
+				Required:         true,
+				ValidateDiagFunc: validateEnvVarContext("dev", "branch-deploy", "deploy-preview", "production"),
 			},
 
 			"value": {
 				Type:        schema.TypeString,
 				Description: "The environment variable's unencrypted value",
 				Required:    true,
+				Sensitive:   true,
 			},
 		},
 	}
@@ -72,7 +62,7 @@ func resourceEnvVarValueCreateOrUpdate(c context.Context, d *schema.ResourceData
 	}
 
 	// perform operation
-	_, err := meta.Netlify.Operations.SetEnvVarValue(params, meta.AuthInfo)
+	_, err := meta.Operations.SetEnvVarValue(params, meta.AuthInfo)
 	if err != nil {
 		// 200 status codes are generally okay
 		if v, ok := err.(*runtime.APIError); !ok || v.Code != 200 {
@@ -95,7 +85,7 @@ func resourceEnvVarValueRead(c context.Context, d *schema.ResourceData, metaRaw
 	params.Key = key
 
 	// perform operation
-	resp, err := meta.Netlify.Operations.GetEnvVar(params, meta.AuthInfo)
+	resp, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
 	if err != nil {
 		// If it is a 404 it was removed remotely
 		if v, ok := err.(*operations.GetEnvVarDefault); ok && v.Code() == 404 {
@@ -124,6 +114,26 @@ func resourceEnvVarValueRead(c context.Context, d *schema.ResourceData, metaRaw
 	return nil
 }
 
+// Returns a validator that accepts the given deploy contexts. The variables
+// of netlify_environment_variables can use `all`, while a single value can't.
+func validateEnvVarContext(contexts ...string) schema.SchemaValidateDiagFunc {
+	return func(value interface{}, path cty.Path) diag.Diagnostics {
+		for _, v := range contexts {
+			if v == value.(string) {
+				return nil
+			}
+		}
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Context level invalid.",
+				Detail:        fmt.Sprintf("Must be one of [`%s`]", strings.Join(contexts, "` `")),
+				AttributePath: path,
+			},
+		}
+	}
+}
+
 func resourceEnvVarValueDelete(c context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
 	meta := metaRaw.(*Meta)
 
@@ -139,7 +149,7 @@ func resourceEnvVarValueDelete(c context.Context, d *schema.ResourceData, metaRa
 	}
 
 	// perform operation
-	_, err := meta.Netlify.Operations.SetEnvVarValue(params, meta.AuthInfo)
+	_, err := meta.Operations.SetEnvVarValue(params, meta.AuthInfo)
 	if err != nil {
 		// default response is OK if it's just the default
 		if v, ok := err.(*operations.SetEnvVarValueDefault); !ok && v == nil {
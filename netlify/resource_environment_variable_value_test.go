@@ -33,7 +33,7 @@ func TestAccEnvVarValue_disappears(t *testing.T) {
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewDeleteSiteParams()
 		params.SiteID = site.ID
-		_, err := meta.Netlify.Operations.DeleteSite(params, meta.AuthInfo)
+		_, err := meta.Operations.DeleteSite(params, meta.AuthInfo)
 		return err
 	}
 
@@ -43,7 +43,7 @@ func TestAccEnvVarValue_disappears(t *testing.T) {
 		params.SiteID = &site.ID
 		params.Key = "var1"
 		meta := testAccProvider.Meta().(*Meta)
-		_, err := meta.Netlify.Operations.GetEnvVar(params, meta.AuthInfo)
+		_, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
 		if err != nil {
 			// If it is a 404 it was removed remotely
 			if v, ok := err.(*operations.GetEnvVarDefault); ok && v.Code() == 404 {
@@ -111,7 +111,7 @@ func testAccCheckEnvVarHasValue(name string, context string, val string) resourc
 
 		// perform operation
 		meta := testAccProvider.Meta().(*Meta)
-		resp, err := meta.Netlify.Operations.GetEnvVar(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,295 @@
+package netlify
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Manages a set of environment variables that share their scopes and deploy
+// context as one resource. Only the keys in variables are read and managed,
+// so other variables of the account or site are left alone. Use
+// netlify_environment_variable instead for variables with values per context.
+func resourceEnvVars() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceEnvVarsCreate,
+		Read:   resourceEnvVarsRead,
+		Update: resourceEnvVarsUpdate,
+		Delete: resourceEnvVarsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:        schema.TypeString,
+				Description: "The account ID / slug to create the environment variables for. If unset, the provider's `default_account_slug` is used.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+
+			"site_id": {
+				Type:        schema.TypeString,
+				Description: "If provided, creates the environment variables on the site level, not the account level",
+				Optional:    true,
+				ForceNew:    true,
+			},
+
+			"variables": {
+				Type:        schema.TypeMap,
+				Description: "The environment variables to set, by key (case-sensitive).",
+				Required:    true,
+				Sensitive:   true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"scopes": {
+				Type:        schema.TypeSet,
+				Description: "The scopes that the environment variables are set to (Pro plans and above)",
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"context": {
+				Type:             schema.TypeString,
+				Description:      "The deploy context in which the values are used. Enum: [`all` `dev` `branch-deploy` `deploy-preview` `production`]",
+				Optional:         true,
+				Default:          models.EnvVarValueContextAll,
+				ValidateDiagFunc: validateEnvVarContext("all", "dev", "branch-deploy", "deploy-preview", "production"),
+			},
+		},
+	}
+}
+
+func resourceEnvVarsCreate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	accountID := meta.accountSlug(d.Get("account_id").(string))
+	if accountID == "" {
+		return fmt.Errorf("account_id must be set when the provider has no default_account_slug")
+	}
+	d.Set("account_id", accountID)
+	siteID := d.Get("site_id").(string)
+
+	if err := resourceEnvVars_create(d, meta, accountID, siteID, d.Get("variables").(map[string]interface{})); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", accountID, siteID))
+	return resourceEnvVarsRead(d, metaRaw)
+}
+
+func resourceEnvVarsRead(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	params := operations.NewGetEnvVarsParams()
+	params.AccountID = d.Get("account_id").(string)
+	if siteID := d.Get("site_id").(string); siteID != "" {
+		params.SiteID = &siteID
+	}
+
+	resp, err := meta.Operations.GetEnvVars(params, meta.AuthInfo)
+	if err != nil {
+		// If it is a 404, the account or site was removed remotely
+		if v, ok := err.(*operations.GetEnvVarsDefault); ok && v.Code() == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	remote := map[string]*models.EnvVar{}
+	for _, envVar := range resp.Payload {
+		remote[envVar.Key] = envVar
+	}
+
+	// Only the managed keys are read back. A key deleted remotely drops out
+	// of the map to be created again, and a key without a value in the
+	// context reads as empty to be updated.
+	var keys []string
+	for key := range d.Get("variables").(map[string]interface{}) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	variables := map[string]interface{}{}
+	var scopes []string
+	for _, key := range keys {
+		envVar, ok := remote[key]
+		if !ok {
+			continue
+		}
+
+		variables[key] = resourceEnvVars_value(envVar, d.Get("context").(string))
+		if scopes == nil {
+			scopes = envVar.Scopes
+		}
+	}
+
+	d.Set("variables", variables)
+	if scopes != nil {
+		d.Set("scopes", scopes)
+	}
+	return nil
+}
+
+func resourceEnvVarsUpdate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	accountID, siteID := d.Get("account_id").(string), d.Get("site_id").(string)
+	o, n := d.GetChange("variables")
+	old, new := o.(map[string]interface{}), n.(map[string]interface{})
+
+	added, updated, removed := resourceEnvVars_changes(old, new, d.HasChanges("scopes", "context"))
+
+	for _, key := range removed {
+		if err := resourceEnvVars_delete(meta, accountID, siteID, key); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range updated {
+		params := operations.NewUpdateEnvVarParams()
+		params.AccountID = accountID
+		if siteID != "" {
+			params.SiteID = &siteID
+		}
+		params.Key = key
+		params.EnvVar = &models.UpdateEnvVarParamsBody{
+			Key:    key,
+			Scopes: resourceEnvVars_scopes(d),
+			Values: []*models.EnvVarValue{
+				{
+					Context: d.Get("context").(string),
+					Value:   value.(string),
+				},
+			},
+		}
+		if _, err := meta.Operations.UpdateEnvVar(params, meta.AuthInfo); err != nil {
+			return fmt.Errorf("Error updating environment variable %s: %s", key, err)
+		}
+	}
+
+	if len(added) > 0 {
+		if err := resourceEnvVars_create(d, meta, accountID, siteID, added); err != nil {
+			return err
+		}
+	}
+
+	return resourceEnvVarsRead(d, metaRaw)
+}
+
+func resourceEnvVarsDelete(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	accountID, siteID := d.Get("account_id").(string), d.Get("site_id").(string)
+	for key := range d.Get("variables").(map[string]interface{}) {
+		if err := resourceEnvVars_delete(meta, accountID, siteID, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Splits the change of variables into the ones to create, update and delete.
+// If the shared scopes or context changed, every kept variable is updated.
+func resourceEnvVars_changes(old map[string]interface{}, new map[string]interface{}, shared bool) (map[string]interface{}, map[string]interface{}, []string) {
+	added, updated := map[string]interface{}{}, map[string]interface{}{}
+	for key, value := range new {
+		oldValue, ok := old[key]
+		if !ok {
+			added[key] = value
+		} else if shared || oldValue != value {
+			updated[key] = value
+		}
+	}
+
+	var removed []string
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	return added, updated, removed
+}
+
+// Creates the given variables in a single request.
+func resourceEnvVars_create(d *schema.ResourceData, meta *Meta, accountID string, siteID string, variables map[string]interface{}) error {
+	params := operations.NewCreateEnvVarsParams()
+	params.AccountID = accountID
+	if siteID != "" {
+		params.SiteID = &siteID
+	}
+
+	var keys []string
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		params.EnvVars = append(params.EnvVars, &models.CreateEnvVarsParamsBodyItems{
+			Key:    key,
+			Scopes: resourceEnvVars_scopes(d),
+			Values: []*models.EnvVarValue{
+				{
+					Context: d.Get("context").(string),
+					Value:   variables[key].(string),
+				},
+			},
+		})
+	}
+
+	_, err := meta.Operations.CreateEnvVars(params, meta.AuthInfo)
+	if err != nil {
+		return fmt.Errorf("Error creating environment variables: %s", err)
+	}
+	return nil
+}
+
+func resourceEnvVars_delete(meta *Meta, accountID string, siteID string, key string) error {
+	params := operations.NewDeleteEnvVarParams()
+	params.AccountID = accountID
+	if siteID != "" {
+		params.SiteID = &siteID
+	}
+	params.Key = key
+	_, err := meta.Operations.DeleteEnvVar(params, meta.AuthInfo)
+	if err != nil {
+		// If it is a 404 it was already deleted, e.g. along with its site
+		if v, ok := err.(*operations.DeleteEnvVarDefault); ok && v.Code() == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error deleting environment variable %s: %s", key, err)
+	}
+	return nil
+}
+
+// Returns the configured scopes, or all scopes if none are configured.
+func resourceEnvVars_scopes(d *schema.ResourceData) []string {
+	scopes := []string{}
+	if v, ok := d.GetOk("scopes"); ok {
+		for _, scope := range v.(*schema.Set).List() {
+			scopes = append(scopes, scope.(string))
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = []string{"builds", "functions", "post_processing", "runtime"}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Returns the value of the variable in the given context.
+func resourceEnvVars_value(envVar *models.EnvVar, context string) string {
+	for _, value := range envVar.Values {
+		if value.Context == context {
+			return value.Value
+		}
+	}
+	return ""
+}
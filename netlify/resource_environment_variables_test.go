@@ -0,0 +1,233 @@
+package netlify
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestAccEnvVars_basic(t *testing.T) {
+	resourceName := "netlify_environment_variables.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvVarsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "variables.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "variables.VAR1", "one"),
+					resource.TestCheckResourceAttr(resourceName, "scopes.#", "4"),
+					testAccCheckEnvVarsValue(resourceName, "VAR2", "two"),
+				),
+			},
+
+			{
+				Config: testAccEnvVarsConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "variables.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "scopes.#", "1"),
+					testAccCheckEnvVarsValue(resourceName, "VAR1", "changed"),
+					testAccCheckEnvVarsValue(resourceName, "VAR3", "three"),
+					testAccCheckEnvVarsDeleted(resourceName, "VAR2"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceEnvVarsCreate_singleRequest(t *testing.T) {
+	var calls int
+	var created []*models.EnvVar
+	ops := &testOperations{
+		createEnvVars: func(params *operations.CreateEnvVarsParams) (*operations.CreateEnvVarsCreated, error) {
+			calls++
+			for _, envVar := range params.EnvVars {
+				if envVar.Values[0].Context != "production" || len(envVar.Scopes) != 4 {
+					t.Errorf("expected %s to share the context and default scopes, got %v", envVar.Key, envVar)
+				}
+				created = append(created, &models.EnvVar{Key: envVar.Key, Scopes: envVar.Scopes, Values: envVar.Values})
+			}
+			return &operations.CreateEnvVarsCreated{}, nil
+		},
+		getEnvVars: func(params *operations.GetEnvVarsParams) (*operations.GetEnvVarsOK, error) {
+			return &operations.GetEnvVarsOK{Payload: created}, nil
+		},
+	}
+
+	d := resourceEnvVars().TestResourceData()
+	d.Set("account_id", "team")
+	d.Set("context", "production")
+	d.Set("variables", map[string]interface{}{"B": "2", "A": "1"})
+	if err := resourceEnvVarsCreate(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var keys []string
+	for _, envVar := range created {
+		keys = append(keys, envVar.Key)
+	}
+	if calls != 1 || !reflect.DeepEqual(keys, []string{"A", "B"}) {
+		t.Errorf("expected A and B to be created in one request, got %d requests for %v", calls, keys)
+	}
+	if actual := d.Get("variables").(map[string]interface{}); !reflect.DeepEqual(actual, map[string]interface{}{"A": "1", "B": "2"}) {
+		t.Errorf("expected the created variables to be read back, got %v", actual)
+	}
+}
+
+func TestValidateEnvVarContext(t *testing.T) {
+	variables := resourceEnvVars().Schema["context"].ValidateDiagFunc
+	value := resourceEnvVarValue().Schema["context"].ValidateDiagFunc
+
+	cases := []struct {
+		validate schema.SchemaValidateDiagFunc
+		context  string
+		valid    bool
+	}{
+		{variables, "all", true},
+		{variables, "production", true},
+		{variables, "staging", false},
+		{value, "all", false},
+		{value, "deploy-preview", true},
+	}
+
+	for _, c := range cases {
+		diags := c.validate(c.context, cty.GetAttrPath("context"))
+		if diags.HasError() == c.valid {
+			t.Errorf("expected %q to be valid=%t, got %v", c.context, c.valid, diags)
+		}
+	}
+}
+
+func TestResourceEnvVars_changes(t *testing.T) {
+	old := map[string]interface{}{"KEPT": "1", "CHANGED": "2", "REMOVED": "3"}
+	new := map[string]interface{}{"KEPT": "1", "CHANGED": "two", "ADDED": "4"}
+
+	added, updated, removed := resourceEnvVars_changes(old, new, false)
+	if !reflect.DeepEqual(added, map[string]interface{}{"ADDED": "4"}) {
+		t.Errorf("unexpected added variables: %v", added)
+	}
+	if !reflect.DeepEqual(updated, map[string]interface{}{"CHANGED": "two"}) {
+		t.Errorf("unexpected updated variables: %v", updated)
+	}
+	if !reflect.DeepEqual(removed, []string{"REMOVED"}) {
+		t.Errorf("unexpected removed variables: %v", removed)
+	}
+
+	// A change of the shared scopes or context updates every kept variable
+	_, updated, _ = resourceEnvVars_changes(old, new, true)
+	if !reflect.DeepEqual(updated, map[string]interface{}{"KEPT": "1", "CHANGED": "two"}) {
+		t.Errorf("expected all kept variables to be updated, got %v", updated)
+	}
+}
+
+func TestResourceEnvVarsRead_managedKeys(t *testing.T) {
+	ops := &testOperations{
+		getEnvVars: func(params *operations.GetEnvVarsParams) (*operations.GetEnvVarsOK, error) {
+			return &operations.GetEnvVarsOK{Payload: []*models.EnvVar{
+				{Key: "MANAGED", Scopes: []string{"builds"}, Values: []*models.EnvVarValue{{Context: "all", Value: "1"}}},
+				{Key: "OTHER_CONTEXT", Scopes: []string{"builds"}, Values: []*models.EnvVarValue{{Context: "production", Value: "2"}}},
+				{Key: "UNMANAGED", Scopes: []string{"runtime"}, Values: []*models.EnvVarValue{{Context: "all", Value: "3"}}},
+			}}, nil
+		},
+	}
+
+	d := resourceEnvVars().TestResourceData()
+	d.SetId("team/site")
+	d.Set("account_id", "team")
+	d.Set("site_id", "site")
+	d.Set("context", "all")
+	d.Set("variables", map[string]interface{}{"MANAGED": "1", "OTHER_CONTEXT": "2", "DELETED": "4"})
+	if err := resourceEnvVarsRead(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{"MANAGED": "1", "OTHER_CONTEXT": ""}
+	if actual := d.Get("variables").(map[string]interface{}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected variables %v, got %v", expected, actual)
+	}
+}
+
+func testAccCheckEnvVarsValue(resourceName string, key string, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", resourceName)
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetEnvVarParams()
+		params.AccountID = rs.Primary.Attributes["account_id"]
+		siteID := rs.Primary.Attributes["site_id"]
+		params.SiteID = &siteID
+		params.Key = key
+		resp, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+
+		if actual := resourceEnvVars_value(resp.Payload, "all"); actual != value {
+			return fmt.Errorf("Expected %s to be %q, got %q", key, value, actual)
+		}
+		return nil
+	}
+}
+
+func testAccCheckEnvVarsDeleted(resourceName string, key string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", resourceName)
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetEnvVarParams()
+		params.AccountID = rs.Primary.Attributes["account_id"]
+		siteID := rs.Primary.Attributes["site_id"]
+		params.SiteID = &siteID
+		params.Key = key
+		_, err := meta.Operations.GetEnvVar(params, meta.AuthInfo)
+		if v, ok := err.(*operations.GetEnvVarDefault); ok && v.Code() == 404 {
+			return nil
+		}
+		return fmt.Errorf("Environment variable %s still exists", key)
+	}
+}
+
+var testAccEnvVarsConfig = `
+resource "netlify_site" "test" {}
+
+resource "netlify_environment_variables" "test" {
+	account_id = netlify_site.test.account_slug
+	site_id = netlify_site.test.id
+
+	variables = {
+		VAR1 = "one"
+		VAR2 = "two"
+	}
+}
+`
+
+var testAccEnvVarsConfigUpdate = `
+resource "netlify_site" "test" {}
+
+resource "netlify_environment_variables" "test" {
+	account_id = netlify_site.test.account_slug
+	site_id = netlify_site.test.id
+	scopes = ["builds"]
+
+	variables = {
+		VAR1 = "changed"
+		VAR3 = "three"
+	}
+}
+`
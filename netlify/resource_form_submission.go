@@ -0,0 +1,92 @@
+package netlify
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Adopts an existing form submission so that destroying the resource deletes
+// it. Submissions can't be created through the API, so creating the resource
+// only checks that the submission exists.
+func resourceFormSubmission() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFormSubmissionCreate,
+		Read:   resourceFormSubmissionRead,
+		Delete: resourceFormSubmissionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"submission_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the existing submission. It is deleted when this resource is destroyed.",
+			},
+
+			"number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFormSubmissionCreate(d *schema.ResourceData, metaRaw interface{}) error {
+	d.SetId(d.Get("submission_id").(string))
+	if err := resourceFormSubmissionRead(d, metaRaw); err != nil {
+		return err
+	}
+
+	if d.Id() == "" {
+		return fmt.Errorf("Form submission %s not found", d.Get("submission_id").(string))
+	}
+	return nil
+}
+
+func resourceFormSubmissionRead(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	params := operations.NewListFormSubmissionParams()
+	params.SubmissionID = d.Id()
+	resp, err := meta.Operations.ListFormSubmission(params, meta.AuthInfo)
+	if err != nil {
+		// If it is a 404 it was removed remotely
+		if v, ok := err.(*operations.ListFormSubmissionDefault); ok && v.Code() == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	if len(resp.Payload) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	submission := resp.Payload[0]
+	d.Set("submission_id", submission.ID)
+	d.Set("number", submission.Number)
+	d.Set("created_at", submission.CreatedAt)
+
+	return nil
+}
+
+func resourceFormSubmissionDelete(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	params := operations.NewDeleteSubmissionParams()
+	params.SubmissionID = d.Id()
+	_, err := meta.Operations.DeleteSubmission(params, meta.AuthInfo)
+	if v, ok := err.(*operations.DeleteSubmissionDefault); ok && v.Code() == 404 {
+		return nil
+	}
+	return err
+}
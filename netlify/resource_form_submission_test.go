@@ -0,0 +1,65 @@
+package netlify
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Deletes a real submission, so it needs one that can be thrown away.
+func TestAccFormSubmission(t *testing.T) {
+	submissionID := os.Getenv("NETLIFY_TEST_DISPOSABLE_SUBMISSION_ID")
+	if submissionID == "" {
+		t.Skip("NETLIFY_TEST_DISPOSABLE_SUBMISSION_ID must be set to test deleting form submissions")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckFormSubmissionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccFormSubmissionConfig, submissionID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netlify_form_submission.test", "id", submissionID),
+					resource.TestCheckResourceAttrSet("netlify_form_submission.test", "created_at"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckFormSubmissionDestroy(s *terraform.State) error {
+	meta := testAccProvider.Meta().(*Meta)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "netlify_form_submission" {
+			continue
+		}
+
+		params := operations.NewListFormSubmissionParams()
+		params.SubmissionID = rs.Primary.ID
+		resp, err := meta.Operations.ListFormSubmission(params, meta.AuthInfo)
+		if err != nil {
+			if v, ok := err.(*operations.ListFormSubmissionDefault); ok && v.Code() == 404 {
+				continue
+			}
+			return err
+		}
+
+		if len(resp.Payload) > 0 {
+			return fmt.Errorf("Form submission still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testAccFormSubmissionConfig = `
+resource "netlify_form_submission" "test" {
+	submission_id = "%s"
+}
+`
@@ -1,17 +1,26 @@
 package netlify
 
 import (
+	"context"
+	"sort"
+
+	"github.com/go-openapi/runtime"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
 )
 
+// Netlify hooks notify about a single event each, so a hook with several
+// events is managed as one Netlify hook per event. Their IDs are tracked in
+// hook_ids, keyed by event, and the ID of the resource is the one of the
+// first event's hook.
 func resourceHook() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceHookCreate,
-		Read:   resourceHookRead,
-		Update: resourceHookUpdate,
-		Delete: resourceHookDelete,
+		Create:        resourceHookCreate,
+		Read:          resourceHookRead,
+		Update:        resourceHookUpdate,
+		Delete:        resourceHookDelete,
+		CustomizeDiff: resourceHookCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -28,84 +37,321 @@ func resourceHook() *schema.Resource {
 			},
 
 			"event": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"event", "events"},
+			},
+
+			"events": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"event", "events"},
+				Description:  "The events to notify about, e.g. `deploy_created` and `deploy_failed`, as an alternative to `event`. Creates one Netlify hook per event.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"hook_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The IDs of the Netlify hooks created for `events`, by event.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 
 			"data": {
-				Type:     schema.TypeMap,
-				Required: true,
+				Type:      schema.TypeMap,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			"signature_secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A shared secret used to sign the requests of `url` hooks, so the receiver can verify them. Netlify doesn't return it, so changes made outside of Terraform aren't detected.",
 			},
 		},
 	}
 }
 
 func resourceHookCreate(d *schema.ResourceData, metaRaw interface{}) error {
-	params := operations.NewCreateHookBySiteIDParams()
-	params.SiteID = d.Get("site_id").(string)
-	params.Hook = resourceHook_struct(d)
-
 	meta := metaRaw.(*Meta)
-	resp, err := meta.Netlify.Operations.CreateHookBySiteID(params, meta.AuthInfo)
+	if v, ok := d.GetOk("events"); ok {
+		ids := map[string]interface{}{}
+		for _, event := range resourceHook_events(v.(*schema.Set)) {
+			id, err := resourceHook_create(d, meta, event)
+			if err != nil {
+				// Keep the hooks created so far in state
+				resourceHook_setIDs(d, ids)
+				return err
+			}
+			ids[event] = id
+		}
+
+		resourceHook_setIDs(d, ids)
+		return resourceHookRead(d, metaRaw)
+	}
+
+	id, err := resourceHook_create(d, meta, d.Get("event").(string))
 	if err != nil {
 		return err
 	}
 
-	d.SetId(resp.Payload.ID)
+	d.SetId(id)
 	return resourceHookRead(d, metaRaw)
 }
 
 func resourceHookRead(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
-	params := operations.NewGetHookParams()
-	params.HookID = d.Id()
-	resp, err := meta.Netlify.Operations.GetHook(params, meta.AuthInfo)
-	if err != nil {
-		// If it is a 404 it was removed remotely
-		if v, ok := err.(*operations.GetHookDefault); ok && v.Code() == 404 {
-			d.SetId("")
-			return nil
-		}
+	if _, ok := d.GetOk("events"); ok {
+		return resourceHook_readEvents(d, meta)
+	}
 
+	hook, err := resourceHook_get(meta, d.Id())
+	if err != nil {
 		return err
 	}
 
-	hook := resp.Payload
+	// If it is nil it was removed remotely
+	if hook == nil {
+		d.SetId("")
+		return nil
+	}
+
 	d.Set("site_id", hook.SiteID)
 	d.Set("type", hook.Type)
 	d.Set("event", hook.Event)
-	d.Set("data", hook.Data)
+	d.Set("data", resourceHook_data(hook.Data))
 
 	return nil
 }
 
 func resourceHookUpdate(d *schema.ResourceData, metaRaw interface{}) error {
-	params := operations.NewUpdateHookParams()
-	params.HookID = d.Id()
-	params.Hook = resourceHook_struct(d)
-
 	meta := metaRaw.(*Meta)
-	_, err := meta.Netlify.Operations.UpdateHook(params, meta.AuthInfo)
-	if err != nil {
-		return err
+	if _, ok := d.GetOk("events"); !ok {
+		if err := resourceHook_update(d, meta, d.Id(), d.Get("event").(string)); err != nil {
+			return err
+		}
+		return resourceHookRead(d, metaRaw)
+	}
+
+	o, n := d.GetChange("events")
+	removed := o.(*schema.Set).Difference(n.(*schema.Set))
+	added := n.(*schema.Set).Difference(o.(*schema.Set))
+
+	ids := map[string]interface{}{}
+	for event, id := range d.Get("hook_ids").(map[string]interface{}) {
+		ids[event] = id
 	}
 
+	for _, event := range resourceHook_events(removed) {
+		if id, ok := ids[event]; ok {
+			if err := resourceHook_delete(meta, id.(string)); err != nil {
+				resourceHook_setIDs(d, ids)
+				return err
+			}
+			delete(ids, event)
+		}
+	}
+
+	if d.HasChanges("site_id", "type", "data", "signature_secret") {
+		for event, id := range ids {
+			if err := resourceHook_update(d, meta, id.(string), event); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, event := range resourceHook_events(added) {
+		id, err := resourceHook_create(d, meta, event)
+		if err != nil {
+			resourceHook_setIDs(d, ids)
+			return err
+		}
+		ids[event] = id
+	}
+
+	resourceHook_setIDs(d, ids)
 	return resourceHookRead(d, metaRaw)
 }
 
 func resourceHookDelete(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
+	if _, ok := d.GetOk("events"); ok {
+		for _, id := range d.Get("hook_ids").(map[string]interface{}) {
+			if err := resourceHook_delete(meta, id.(string)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	params := operations.NewDeleteHookParams()
 	params.HookID = d.Id()
-	_, err := meta.Netlify.Operations.DeleteHook(params, meta.AuthInfo)
+	_, err := meta.Operations.DeleteHook(params, meta.AuthInfo)
 	return err
 }
 
-// Returns the Hook structure that can be used for creation or updating.
-func resourceHook_struct(d *schema.ResourceData) *models.Hook {
+// Switching between event and events changes how many Netlify hooks the
+// resource manages, so it replaces them.
+func resourceHookCustomizeDiff(c context.Context, d *schema.ResourceDiff, metaRaw interface{}) error {
+	if d.Id() == "" || !d.HasChange("events") {
+		return nil
+	}
+
+	o, n := d.GetChange("events")
+	if o.(*schema.Set).Len() == 0 || n.(*schema.Set).Len() == 0 {
+		return d.ForceNew("events")
+	}
+	return nil
+}
+
+// Reads the hooks of every event, dropping the ones removed remotely.
+func resourceHook_readEvents(d *schema.ResourceData, meta *Meta) error {
+	ids := map[string]interface{}{}
+	var first *models.Hook
+	for _, event := range resourceHook_events(d.Get("events").(*schema.Set)) {
+		id, ok := d.Get("hook_ids").(map[string]interface{})[event]
+		if !ok {
+			continue
+		}
+
+		hook, err := resourceHook_get(meta, id.(string))
+		if err != nil {
+			return err
+		}
+		if hook == nil {
+			continue
+		}
+
+		ids[event] = hook.ID
+		if first == nil {
+			first = hook
+		}
+	}
+
+	var events []string
+	for event := range ids {
+		events = append(events, event)
+	}
+
+	resourceHook_setIDs(d, ids)
+	d.Set("events", events)
+	if first != nil {
+		d.Set("site_id", first.SiteID)
+		d.Set("type", first.Type)
+		d.Set("data", resourceHook_data(first.Data))
+	}
+
+	return nil
+}
+
+// Sets hook_ids, and the ID of the resource to the hook of the first event.
+func resourceHook_setIDs(d *schema.ResourceData, ids map[string]interface{}) {
+	d.Set("hook_ids", ids)
+
+	var events []string
+	for event := range ids {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+
+	d.SetId("")
+	if len(events) > 0 {
+		d.SetId(ids[events[0]].(string))
+	}
+}
+
+// Returns the events of the set in a stable order.
+func resourceHook_events(set *schema.Set) []string {
+	var events []string
+	for _, event := range set.List() {
+		events = append(events, event.(string))
+	}
+	sort.Strings(events)
+	return events
+}
+
+// Creates a hook for the event, returning its ID.
+func resourceHook_create(d *schema.ResourceData, meta *Meta, event string) (string, error) {
+	params := operations.NewCreateHookBySiteIDParams()
+	params.SiteID = d.Get("site_id").(string)
+	params.Hook = resourceHook_struct(d, event)
+
+	resp, err := meta.Operations.CreateHookBySiteID(params, meta.AuthInfo)
+	if err != nil {
+		return "", err
+	}
+	return resp.Payload.ID, nil
+}
+
+func resourceHook_update(d *schema.ResourceData, meta *Meta, id string, event string) error {
+	params := operations.NewUpdateHookParams()
+	params.HookID = id
+	params.Hook = resourceHook_struct(d, event)
+
+	_, err := meta.Operations.UpdateHook(params, meta.AuthInfo)
+	return err
+}
+
+// Returns the hook with the given ID, or nil if it doesn't exist.
+func resourceHook_get(meta *Meta, id string) (*models.Hook, error) {
+	params := operations.NewGetHookParams()
+	params.HookID = id
+	resp, err := meta.Operations.GetHook(params, meta.AuthInfo)
+	if err != nil {
+		if v, ok := err.(*operations.GetHookDefault); ok && v.Code() == 404 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Payload, nil
+}
+
+// Deletes the hook with the given ID, ignoring hooks that are already gone.
+func resourceHook_delete(meta *Meta, id string) error {
+	params := operations.NewDeleteHookParams()
+	params.HookID = id
+	_, err := meta.Operations.DeleteHook(params, meta.AuthInfo)
+	if v, ok := err.(*runtime.APIError); ok && v.Code == 404 {
+		return nil
+	}
+	return err
+}
+
+// Returns the Hook structure for the event that can be used for creation or
+// updating.
+func resourceHook_struct(d *schema.ResourceData, event string) *models.Hook {
+	data := make(map[string]interface{})
+	for k, v := range d.Get("data").(map[string]interface{}) {
+		data[k] = v
+	}
+	if v, ok := d.GetOk("signature_secret"); ok {
+		data[hookSignatureSecretKey] = v.(string)
+	}
+
 	return &models.Hook{
-		Data:  d.Get("data").(map[string]interface{}),
-		Event: d.Get("event").(string),
+		Data:  data,
+		Event: event,
 		Type:  d.Get("type").(string),
 	}
 }
+
+// The key of the hook data Netlify signs outgoing requests with.
+const hookSignatureSecretKey = "signature_secret"
+
+// Returns the data of a hook without its signature secret, which is tracked
+// separately so it doesn't show up as a diff in `data`.
+func resourceHook_data(raw interface{}) map[string]interface{} {
+	data := make(map[string]interface{})
+	m, _ := raw.(map[string]interface{})
+	for k, v := range m {
+		if k != hookSignatureSecretKey {
+			data[k] = v
+		}
+	}
+	return data
+}
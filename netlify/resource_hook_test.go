@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
@@ -43,7 +44,7 @@ func TestAccHook_disappears(t *testing.T) {
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewDeleteHookParams()
 		params.HookID = hook.ID
-		_, err := meta.Netlify.Operations.DeleteHook(params, meta.AuthInfo)
+		_, err := meta.Operations.DeleteHook(params, meta.AuthInfo)
 		return err
 	}
 
@@ -98,6 +99,138 @@ func TestAccHook_updateData(t *testing.T) {
 	})
 }
 
+func TestAccHook_signatureSecret(t *testing.T) {
+	var hook models.Hook
+	resourceName := "netlify_hook.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccHookConfig_signatureSecret, "first-secret"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckHookExists(resourceName, &hook),
+					resource.TestCheckResourceAttr(resourceName, "signature_secret", "first-secret"),
+					resource.TestCheckResourceAttr(resourceName, "data.%", "1"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccHookConfig_signatureSecret, "second-secret"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckHookExists(resourceName, &hook),
+					resource.TestCheckResourceAttr(resourceName, "signature_secret", "second-secret"),
+					resource.TestCheckResourceAttr(resourceName, "data.url", "http://www.example.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceHookStruct_signatureSecret(t *testing.T) {
+	d := resourceHook().TestResourceData()
+	d.Set("data", map[string]interface{}{"url": "http://www.example.com"})
+	d.Set("signature_secret", "secret")
+
+	data := resourceHook_struct(d, "deploy_created").Data.(map[string]interface{})
+	if data["url"] != "http://www.example.com" || data["signature_secret"] != "secret" {
+		t.Errorf("expected the secret to be sent along with the data, got %v", data)
+	}
+
+	data = resourceHook_data(data)
+	if _, ok := data["signature_secret"]; ok || data["url"] != "http://www.example.com" {
+		t.Errorf("expected the secret to be left out of the read data, got %v", data)
+	}
+}
+
+func TestAccHook_events(t *testing.T) {
+	resourceName := "netlify_hook.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccHookConfig_events, `"deploy_created", "deploy_failed"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "events.#", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "hook_ids.deploy_created"),
+					resource.TestCheckResourceAttrSet(resourceName, "hook_ids.deploy_failed"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccHookConfig_events, `"deploy_failed", "deploy_locked"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "hook_ids.%", "2"),
+					resource.TestCheckNoResourceAttr(resourceName, "hook_ids.deploy_created"),
+					resource.TestCheckResourceAttrSet(resourceName, "hook_ids.deploy_locked"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceHook_events(t *testing.T) {
+	hooks := map[string]*models.Hook{}
+	ops := &testOperations{
+		createHookBySiteID: func(params *operations.CreateHookBySiteIDParams) (*operations.CreateHookBySiteIDCreated, error) {
+			hook := *params.Hook
+			hook.ID = "hook-" + hook.Event
+			hook.SiteID = params.SiteID
+			hooks[hook.ID] = &hook
+			return &operations.CreateHookBySiteIDCreated{Payload: &hook}, nil
+		},
+		getHook: func(params *operations.GetHookParams) (*operations.GetHookOK, error) {
+			hook, ok := hooks[params.HookID]
+			if !ok {
+				return nil, operations.NewGetHookDefault(404)
+			}
+			return &operations.GetHookOK{Payload: hook}, nil
+		},
+		deleteHook: func(params *operations.DeleteHookParams) (*operations.DeleteHookNoContent, error) {
+			delete(hooks, params.HookID)
+			return &operations.DeleteHookNoContent{}, nil
+		},
+	}
+	meta := testMeta(ops)
+
+	d := resourceHook().TestResourceData()
+	d.Set("site_id", "site")
+	d.Set("type", "url")
+	d.Set("events", []interface{}{"deploy_failed", "deploy_created"})
+	d.Set("data", map[string]interface{}{"url": "http://www.example.com"})
+	if err := resourceHookCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(hooks) != 2 || hooks["hook-deploy_created"] == nil || hooks["hook-deploy_failed"] == nil {
+		t.Fatalf("expected a hook per event, got %v", hooks)
+	}
+	if d.Id() != "hook-deploy_created" || d.Get("hook_ids.deploy_failed").(string) != "hook-deploy_failed" {
+		t.Errorf("expected the hooks to be tracked by event, got ID %q and %v", d.Id(), d.Get("hook_ids"))
+	}
+
+	// A hook removed remotely drops out of events to be created again
+	delete(hooks, "hook-deploy_created")
+	if err := resourceHookRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "hook-deploy_failed" || d.Get("events").(*schema.Set).Len() != 1 {
+		t.Errorf("expected only deploy_failed to be left, got ID %q and events %v", d.Id(), d.Get("events"))
+	}
+
+	if err := resourceHookDelete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(hooks) != 0 {
+		t.Errorf("expected every hook to be deleted, got %v", hooks)
+	}
+}
+
 func testAccCheckHookExists(n string, hook *models.Hook) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -112,7 +245,7 @@ func testAccCheckHookExists(n string, hook *models.Hook) resource.TestCheckFunc
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewGetHookParams()
 		params.HookID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetHook(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetHook(params, meta.AuthInfo)
 		if err != nil {
 			return err
 		}
@@ -131,7 +264,7 @@ func testAccCheckHookDestroy(s *terraform.State) error {
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewGetHookParams()
 		params.HookID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetHook(params, meta.AuthInfo)
+		resp, err := meta.Operations.GetHook(params, meta.AuthInfo)
 		if err == nil && resp.Payload != nil {
 			return fmt.Errorf("Hook still exists: %s", rs.Primary.ID)
 		}
@@ -172,3 +305,30 @@ resource "netlify_hook" "test" {
 	}
 }
 `
+
+var testAccHookConfig_signatureSecret = `
+resource "netlify_site" "test" {}
+
+resource "netlify_hook" "test" {
+	site_id = "${netlify_site.test.id}"
+	type  = "url"
+	event = "deploy_created"
+	data  = {
+		url = "http://www.example.com"
+	}
+	signature_secret = "%s"
+}
+`
+
+var testAccHookConfig_events = `
+resource "netlify_site" "test" {}
+
+resource "netlify_hook" "test" {
+	site_id = "${netlify_site.test.id}"
+	type  = "url"
+	events = [%s]
+	data  = {
+		url = "http://www.example.com"
+	}
+}
+`
@@ -1,6 +1,18 @@
 package netlify
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
@@ -8,13 +20,14 @@ import (
 
 func resourceSite() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSiteCreate,
-		Read:   resourceSiteRead,
-		Update: resourceSiteUpdate,
-		Delete: resourceSiteDelete,
+		Create:      resourceSiteCreate,
+		ReadContext: resourceSiteReadContext,
+		Update:      resourceSiteUpdate,
+		Delete:      resourceSiteDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceSiteImport,
 		},
+		CustomizeDiff: resourceSiteCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -23,20 +36,71 @@ func resourceSite() *schema.Resource {
 				Computed: true,
 			},
 
+			"allow_rename": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Must be set to `true` to change the `name` of an existing site, which also changes its `*.netlify.app` subdomain.",
+			},
+
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether destroying the site fails instead of deleting it along with all of its deploys. Set it to `false` and apply before destroying a protected site.",
+			},
+
 			"custom_domain": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 
+			"default_domain": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Netlify subdomain of the site, e.g. `mysite.netlify.app`, which stays reachable whether or not `custom_domain` is set. Point CNAME records for the custom domain at it.",
+			},
+
+			"domain_aliases": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Additional domains the site is served on.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"prerender": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Set to `netlify` to serve prerendered pages to crawlers. The Netlify API has no further crawler or prerendering settings.",
+				ValidateDiagFunc: resourceSite_validatePrerender,
+			},
+
+			"provision_certificate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to ask Netlify to provision a new Let's Encrypt certificate whenever `custom_domain` or `domain_aliases` change, so that it covers the new domains. The covered domains are exported in `ssl`.",
+			},
+
+			"force_ssl": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to redirect HTTP requests to HTTPS. The site's custom domains need a certificate first, so with a `custom_domain` this is only allowed once `ssl` has a certificate or `provision_certificate` is set; it is enabled after the certificate has been requested. Read from Netlify if not set.",
+			},
+
 			"deploy_url": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 
 			"account_slug": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The slug of the team the site belongs to. Changing it transfers the site to the other team in place, keeping its deploys, domains and settings.",
 			},
 
 			"account_name": {
@@ -44,6 +108,217 @@ func resourceSite() *schema.Resource {
 				Computed: true,
 			},
 
+			"account_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the plan of the site's team, e.g. `starter`. Only read when the provider's `read_account_types` is set, and empty otherwise.",
+			},
+
+			"plan": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The plan of the site itself as reported by Netlify. The API has no per-site rate limits; the limits of the team's plan are exported by the `netlify_account_capabilities` data source.",
+			},
+
+			"ssl": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The TLS certificate covering the site's custom domains. Empty until a certificate has been provisioned.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"domains": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+
+						"expires_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"tls_covered_domains": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The `custom_domain` and `domain_aliases` that the certificate in `ssl` covers, directly or by a wildcard. A domain missing from this list is not served over HTTPS yet. Empty without a certificate.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"capabilities": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The features of the site's plan, e.g. `form_processing` or `split_testing`. Values are strings: `\"true\"` or `\"false\"` for toggles, and JSON for nested settings.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"created_via": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the site was created as reported by Netlify, e.g. through the UI or the API, to tell sites created outside of Terraform apart.",
+			},
+
+			"last_deploy_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the site's most recent deploy, e.g. `ready`, `building` or `error`, which can differ from the published deploy's when the latest build failed. Only read when the provider's `read_last_deploy_states` is set, and empty otherwise or if the site has no deploys.",
+			},
+
+			"published_deploy": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The deploy currently published on the site. Empty until the site has been deployed.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"commit_ref": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"branch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"deploy_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"build_hooks": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The build hooks configured on the site, including those created outside of Terraform. Their secret URLs are left out; manage a hook with `netlify_build_hook` to get its URL. Only read when the provider's `read_build_hooks` is set, and empty otherwise.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"branch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"dns_managed_by_netlify": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the `custom_domain` is served from a Netlify DNS zone rather than external DNS.",
+			},
+
+			"dns_zone_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the Netlify DNS zone serving `custom_domain`, if any.",
+			},
+
+			"dns_zone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of a Netlify DNS zone to create for `custom_domain`, e.g. `example.com`, if the team doesn't have it yet. Requires `managed_dns`. The zone is left in place when the site is destroyed or this is unset.",
+			},
+
+			"dns_zone_name_servers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The name servers of the Netlify DNS zone serving `custom_domain`, to delegate the domain to at its registrar.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"managed_dns": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to have Netlify create the default records for `custom_domain` in the team's Netlify DNS zone. Turning it off leaves the records in place.",
+			},
+
+			"managed_dns_records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The records Netlify manages for the site when `managed_dns` is set.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"builds_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether pushes to the linked repo trigger builds. Set to `false` to stop builds.",
+			},
+
+			"private_build_logs": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the site's deploy logs are only visible to team members.",
+			},
+
+			"deploy_previews": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether pull requests against the linked repo get deploy previews.",
+			},
+
 			"repo": {
 				Type:     schema.TypeList,
 				MaxItems: 1,
@@ -56,8 +331,16 @@ func resourceSite() *schema.Resource {
 						},
 
 						"deploy_key_id": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The ID of the deploy key Netlify clones the repo with. Changing it rotates the key in place.",
+						},
+
+						"deploy_key_public_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The public key of an existing deploy key to use when `deploy_key_id` is not set.",
 						},
 
 						"dir": {
@@ -65,6 +348,41 @@ func resourceSite() *schema.Resource {
 							Optional: true,
 						},
 
+						"functions_dir": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Directory containing the site's functions. Must not be inside `dir`.",
+						},
+
+						"package_path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Directory of the package to build in a monorepo, relative to the repo root, for framework presets that build a package other than the base directory.",
+						},
+
+						"framework": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The framework preset Netlify builds the site with, e.g. `next` or `astro`. Netlify detects it from the repo if unset; set it to override a wrongly detected preset.",
+						},
+
+						"build_env": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Build time environment variables, stored in the site's legacy build settings. The values are sensitive, so they are hidden in plans. For scopes or deploy contexts use `netlify_environment_variable` instead, and don't manage the same site with both. Only read back from Netlify and sent to it once set, so variables managed elsewhere are left alone.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+
+						"ignore_command": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Shell command that decides whether to skip a build; Netlify skips the build when it exits with code 0. The API has no path based build settings, so use this to skip builds when only certain paths changed, e.g. `git diff --quiet $CACHED_COMMIT_REF $COMMIT_REF -- site/`.",
+						},
+
 						"provider": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -80,9 +398,55 @@ func resourceSite() *schema.Resource {
 							Required: true,
 						},
 
+						"public_repo": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							Description: "Whether Netlify treats the repo as public. If set, a change of the flag on Netlify, e.g. after the repo's visibility changed, shows up in the plan.",
+						},
+
+						"repo_id": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "The git provider's numeric ID of the repo, which stays the same when the repo is renamed.",
+						},
+
+						"repo_url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL of the connected repo on the git provider, e.g. `https://github.com/owner/repo`.",
+						},
+
 						"installation_id": {
-							Type:     schema.TypeInt,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "The ID of the git provider app installation with access to the repo, required for private repos.",
+						},
+
+						"git_provider_uses_installation": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether to connect the repo through the git provider's app installation (e.g. the Netlify GitHub App) rather than OAuth and a deploy key. When set without `installation_id`, the installation is looked up from the account's existing sites for the same repo owner. Only used when creating the site.",
+						},
+
+						"allowed_branches": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Computed:    true,
+							Description: "The branches to deploy in addition to `repo_branch`. Don't combine with `netlify_branch_deploy` resources for the same site.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+
+						"deploy_hook": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Sensitive:   true,
+							Description: "The deploy hook URL Netlify configured on the git provider for the connected repo.",
 						},
 					},
 				},
@@ -97,43 +461,112 @@ func resourceSiteCreate(d *schema.ResourceData, metaRaw interface{}) error {
 	// If we are trying to create a site using a private repository (i.e. not
 	// a public_repo) then we need to get an installation id for the provider.
 	// The easiest (and only way I can see) of doing this is by querying a user's
-	// pre-existing sites and getting it from there.
+	// pre-existing sites and getting it from there, which we do when
+	// git_provider_uses_installation is set.
+
+	setup, err := resourceSite_setupStruct(d, meta)
+	if err != nil {
+		return err
+	}
+	if err := resourceSite_resolveInstallation(d, meta, setup); err != nil {
+		return err
+	}
 
-	// If we have an "account_slug" set we use a different API path that lets
-	// us create a site in a specific team. Unfortunately we have to duplicate
-	// a lot of stuff because the types are totally different even though
-	// structurally they are identical.
+	// If we have an "account_slug" set (or a default one from the provider) we
+	// use a different API path that lets us create a site in a specific team.
+	// Both take the same setup, so the repo (including its deploy key) is
+	// configured the same way on either path.
 	var site *models.Site
-	if v, ok := d.GetOk("account_slug"); ok {
+	if slug := meta.accountSlug(d.Get("account_slug").(string)); slug != "" {
 		params := operations.NewCreateSiteInTeamParams()
-		params.AccountSlug = v.(string)
-		params.Site = resourceSite_setupStruct(d)
-		resp, err := meta.Netlify.Operations.CreateSiteInTeam(params, meta.AuthInfo)
+		params.AccountSlug = slug
+		params.Site = setup
+		resp, err := meta.Operations.CreateSiteInTeam(params, meta.AuthInfo)
 		if err != nil {
-			return err
+			return resourceSite_domainConflict(d, err)
 		}
 
 		site = resp.Payload
 	} else {
 		params := operations.NewCreateSiteParams()
-		params.Site = resourceSite_setupStruct(d)
-		resp, err := meta.Netlify.Operations.CreateSite(params, meta.AuthInfo)
+		params.Site = setup
+		resp, err := meta.Operations.CreateSite(params, meta.AuthInfo)
 		if err != nil {
-			return err
+			return resourceSite_domainConflict(d, err)
 		}
 
 		site = resp.Payload
 	}
 
 	d.SetId(site.ID)
-	return resourceSiteRead(d, metaRaw)
+
+	if name := d.Get("dns_zone").(string); name != "" {
+		if err := resourceSite_ensureDnsZone(meta, d.Id(), site.AccountSlug, name); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("managed_dns").(bool) {
+		if err := resourceSite_configureDns(meta, d.Id()); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("provision_certificate").(bool) && d.Get("custom_domain").(string) != "" {
+		if err := resourceSite_provisionCertificate(meta, d.Id()); err != nil {
+			return err
+		}
+	}
+
+	// Only force HTTPS once the certificate has been requested
+	if _, ok := d.GetOk("force_ssl"); ok {
+		if err := patchSite(meta, d.Id(), map[string]interface{}{"force_ssl": true}); err != nil {
+			return err
+		}
+	}
+
+	// Only patch the build settings when one of them differs from Netlify's defaults
+	patch := !d.Get("builds_enabled").(bool) || !d.Get("deploy_previews").(bool) || d.Get("private_build_logs").(bool) ||
+		d.Get("repo.0.ignore_command").(string) != "" || d.Get("repo.0.package_path").(string) != "" ||
+		d.Get("repo.0.framework").(string) != "" || len(d.Get("repo.0.build_env").(map[string]interface{})) > 0
+	if patch {
+		if err := resourceSite_patchBuildSettings(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return resourceSite_readAfterCreate(d, metaRaw, siteReadRetryTimeout)
+}
+
+// How long to keep retrying the read of a site that was just created.
+const siteReadRetryTimeout = 2 * time.Minute
+
+// Reads a site that was just created. The site exists at this point, so
+// failing the create on a transient error would leave it behind without
+// state; those are retried instead. A 404 is still handled by the read.
+func resourceSite_readAfterCreate(d *schema.ResourceData, metaRaw interface{}, timeout time.Duration) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
+		err := resourceSiteRead(d, metaRaw)
+		if isTransientError(err) {
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+}
+
+// Reports whether err is a server error from the API, which may succeed when
+// retried. Rate limits are already retried by the client's transport.
+func isTransientError(err error) bool {
+	v, ok := err.(interface{ Code() int })
+	return ok && v.Code() >= 500
 }
 
 func resourceSiteRead(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
-	params := operations.NewGetSiteParams()
-	params.SiteID = d.Id()
-	resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
+	site, rawFields, err := getSite(meta, d.Id())
 	if err != nil {
 		// If it is a 404 it was removed remotely
 		if v, ok := err.(*operations.GetSiteDefault); ok && v.Code() == 404 {
@@ -144,24 +577,107 @@ func resourceSiteRead(d *schema.ResourceData, metaRaw interface{}) error {
 		return err
 	}
 
-	site := resp.Payload
 	d.Set("name", site.Name)
 	d.Set("custom_domain", site.CustomDomain)
+	d.Set("default_domain", rawFields.DefaultDomain)
+	d.Set("domain_aliases", site.DomainAliases)
+	d.Set("prerender", site.Prerender)
+	d.Set("force_ssl", site.ForceSsl)
 	d.Set("deploy_url", site.DeployURL)
 	d.Set("account_slug", site.AccountSlug)
 	d.Set("account_name", site.AccountName)
+	d.Set("created_via", rawFields.CreatedVia)
+	d.Set("plan", site.Plan)
+
+	accountType, err := resourceSite_accountType(meta, site.AccountSlug)
+	if err != nil {
+		return err
+	}
+	d.Set("account_type", accountType)
+
+	buildSettings := rawFields.BuildSettings
+	// Builds can be stopped from the UI even without a linked repo, in which
+	// case the typed build settings may come back empty, so read the raw flag.
+	d.Set("builds_enabled", buildSettings["stop_builds"] != true)
+	d.Set("deploy_previews", buildSettings["skip_prs"] != true)
+	d.Set("private_build_logs", site.BuildSettings != nil && site.BuildSettings.PrivateLogs)
+
+	zone, err := resourceSite_dnsZone(meta, site)
+	if err != nil {
+		return err
+	}
+	d.Set("dns_managed_by_netlify", zone != nil)
+	d.Set("dns_zone_id", "")
+	d.Set("dns_zone_name_servers", []string{})
+	if zone != nil {
+		d.Set("dns_zone_id", zone.ID)
+		d.Set("dns_zone_name_servers", zone.DNSServers)
+	}
+
+	records := []interface{}{}
+	if zone != nil && d.Get("managed_dns").(bool) {
+		records, err = resourceSite_managedDnsRecords(meta, site, zone)
+		if err != nil {
+			return err
+		}
+	}
+	d.Set("managed_dns_records", records)
+
+	ssl, err := resourceSite_ssl(meta, site)
+	if err != nil {
+		return err
+	}
+	d.Set("ssl", ssl)
+	var certDomains []string
+	if len(ssl) > 0 {
+		certDomains = ssl[0].(map[string]interface{})["domains"].([]string)
+	}
+	d.Set("tls_covered_domains", resourceSite_tlsCoveredDomains(site, certDomains))
+	d.Set("published_deploy", resourceSite_publishedDeploy(site))
+
+	lastDeployState, err := resourceSite_lastDeployState(meta, site.ID)
+	if err != nil {
+		return err
+	}
+	d.Set("last_deploy_state", lastDeployState)
+
+	buildHooks, err := resourceSite_buildHooks(meta, site.ID)
+	if err != nil {
+		return err
+	}
+	d.Set("build_hooks", buildHooks)
+	d.Set("capabilities", resourceSite_capabilities(site.Capabilities))
 	d.Set("repo", nil)
 
-	if site.BuildSettings != nil && site.BuildSettings.RepoPath != "" {
+	// A site linked by a deploy key can come back with only some of its repo
+	// fields, e.g. right after importing it, so the repo is kept as long as
+	// there is a deploy key rather than dropped and planned to be relinked.
+	if site.BuildSettings != nil && (site.BuildSettings.RepoPath != "" || site.BuildSettings.DeployKeyID != "") {
+		ignoreCommand, _ := buildSettings["ignore"].(string)
+		packagePath, _ := buildSettings["package_path"].(string)
+		framework, _ := buildSettings["framework"].(string)
+		provider, repoPath, repoBranch := resourceSite_repoIdentity(d, site.BuildSettings)
 		d.Set("repo", []interface{}{
 			map[string]interface{}{
-				"command":         site.BuildSettings.Cmd,
-				"deploy_key_id":   site.BuildSettings.DeployKeyID,
-				"dir":             site.BuildSettings.Dir,
-				"provider":        site.BuildSettings.Provider,
-				"repo_path":       site.BuildSettings.RepoPath,
-				"repo_branch":     site.BuildSettings.RepoBranch,
-				"installation_id": site.BuildSettings.InstallationID,
+				"command":                        site.BuildSettings.Cmd,
+				"deploy_key_id":                  site.BuildSettings.DeployKeyID,
+				"deploy_key_public_key":          d.Get("repo.0.deploy_key_public_key"),
+				"dir":                            site.BuildSettings.Dir,
+				"functions_dir":                  site.BuildSettings.FunctionsDir,
+				"ignore_command":                 ignoreCommand,
+				"build_env":                      resourceSite_buildEnv(d, site.BuildSettings.Env),
+				"package_path":                   packagePath,
+				"framework":                      framework,
+				"provider":                       provider,
+				"repo_path":                      repoPath,
+				"repo_branch":                    repoBranch,
+				"repo_id":                        site.BuildSettings.ID,
+				"repo_url":                       site.BuildSettings.RepoURL,
+				"public_repo":                    site.BuildSettings.PublicRepo,
+				"installation_id":                site.BuildSettings.InstallationID,
+				"git_provider_uses_installation": d.Get("repo.0.git_provider_uses_installation"),
+				"deploy_hook":                    site.DeployHook,
+				"allowed_branches":               resourceSite_allowedBranches(d, site.BuildSettings),
 			},
 		})
 	}
@@ -169,52 +685,907 @@ func resourceSiteRead(d *schema.ResourceData, metaRaw interface{}) error {
 	return nil
 }
 
-func resourceSiteUpdate(d *schema.ResourceData, metaRaw interface{}) error {
-	params := operations.NewUpdateSiteParams()
-	params.Site = resourceSite_setupStruct(d)
-	params.SiteID = d.Id()
+// Reads the site and, if the provider is configured to, warns when nothing
+// points its custom domain at it.
+func resourceSiteReadContext(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	if err := resourceSiteRead(d, metaRaw); err != nil {
+		return diag.FromErr(err)
+	}
 
 	meta := metaRaw.(*Meta)
-	_, err := meta.Netlify.Operations.UpdateSite(params, meta.AuthInfo)
-	if err != nil {
-		return err
+	if !meta.warnOnMissingDns || d.Id() == "" {
+		return nil
 	}
-
-	return resourceSiteRead(d, metaRaw)
+	return resourceSite_dnsWarning(meta, d)
 }
 
-func resourceSiteDelete(d *schema.ResourceData, metaRaw interface{}) error {
+func resourceSiteUpdate(d *schema.ResourceData, metaRaw interface{}) error {
 	meta := metaRaw.(*Meta)
-	params := operations.NewDeleteSiteParams()
-	params.SiteID = d.Id()
-	_, err := meta.Netlify.Operations.DeleteSite(params, meta.AuthInfo)
-	return err
-}
+	if slug := d.Get("account_slug").(string); d.HasChange("account_slug") && slug != "" {
+		if err := resourceSite_transfer(meta, d.Id(), slug); err != nil {
+			return err
+		}
+	}
 
-// Returns the SiteSetup structure that can be used for creation or updating.
-func resourceSite_setupStruct(d *schema.ResourceData) *models.SiteSetup {
-	result := &models.SiteSetup{
-		Site: models.Site{
-			Name:         d.Get("name").(string),
-			CustomDomain: d.Get("custom_domain").(string),
-		},
+	setup, err := resourceSite_setupStruct(d, meta)
+	if err != nil {
+		return err
 	}
 
-	// If we have a repo config, then configure that
-	if v, ok := d.GetOk("repo"); ok {
-		vL := v.([]interface{})
-		repo := vL[0].(map[string]interface{})
+	params := operations.NewUpdateSiteParams()
+	params.Site = setup
+	params.SiteID = d.Id()
+	_, err = meta.Operations.UpdateSite(params, meta.AuthInfo)
+	if err != nil {
+		return resourceSite_domainConflict(d, err)
+	}
 
-		result.Repo = &models.RepoInfo{
-			Cmd:            repo["command"].(string),
+	// Omitting the repo from the update leaves the remote repo connected, so
+	// if the repo block was removed we need to explicitly unlink it.
+	if d.HasChange("repo") {
+		if v, ok := d.GetOk("repo"); !ok || len(v.([]interface{})) == 0 {
+			unlink := operations.NewUnlinkSiteRepoParams()
+			unlink.SiteID = d.Id()
+			_, err := meta.Operations.UnlinkSiteRepo(unlink, meta.AuthInfo)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if name := d.Get("dns_zone").(string); name != "" && d.HasChanges("dns_zone", "custom_domain") {
+		if err := resourceSite_ensureDnsZone(meta, d.Id(), d.Get("account_slug").(string), name); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("managed_dns").(bool) && d.HasChanges("managed_dns", "custom_domain", "dns_zone") {
+		if err := resourceSite_configureDns(meta, d.Id()); err != nil {
+			return err
+		}
+	}
+
+	// SiteSetup drops an empty prerender or custom_domain, so turning
+	// prerendering off or removing the domain needs a patch
+	cleared := map[string]interface{}{}
+	if d.HasChange("prerender") && d.Get("prerender").(string) == "" {
+		cleared["prerender"] = nil
+	}
+	if d.HasChange("custom_domain") && d.Get("custom_domain").(string) == "" {
+		cleared["custom_domain"] = ""
+	}
+	if len(cleared) > 0 {
+		if err := patchSite(meta, d.Id(), cleared); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("provision_certificate").(bool) && d.HasChanges("custom_domain", "domain_aliases") {
+		if err := resourceSite_provisionCertificate(meta, d.Id()); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("force_ssl") {
+		if err := patchSite(meta, d.Id(), map[string]interface{}{"force_ssl": d.Get("force_ssl").(bool)}); err != nil {
+			return err
+		}
+	}
+
+	// SiteSetup drops an empty command, so clearing it needs a patch as well
+	if d.HasChanges("builds_enabled", "deploy_previews", "private_build_logs", "repo.0.command", "repo.0.public_repo", "repo.0.ignore_command", "repo.0.package_path", "repo.0.functions_dir", "repo.0.framework", "repo.0.build_env") {
+		if err := resourceSite_patchBuildSettings(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return resourceSiteRead(d, metaRaw)
+}
+
+// Moves a site to another team the token is a member of. The API spec has no
+// dedicated transfer operation, so this patches the site's account_slug and
+// then checks that the site actually moved.
+func resourceSite_transfer(meta *Meta, siteID string, slug string) error {
+	if err := patchSite(meta, siteID, map[string]interface{}{"account_slug": slug}); err != nil {
+		return fmt.Errorf("Error transferring site %s to team %s: %s", siteID, slug, err)
+	}
+
+	site, _, err := getSite(meta, siteID)
+	if err != nil {
+		return err
+	}
+	if site.AccountSlug != slug {
+		return fmt.Errorf("Site %s is still in team %s after transferring it to team %s; check that the token is an owner of both teams", siteID, site.AccountSlug, slug)
+	}
+	return nil
+}
+
+func resourceSiteDelete(d *schema.ResourceData, metaRaw interface{}) error {
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("Site %s has deletion_protection enabled; set it to false and apply before destroying the site", d.Id())
+	}
+
+	meta := metaRaw.(*Meta)
+	if err := resourceSite_deleteEnvVars(meta, d.Get("account_slug").(string), d.Id()); err != nil {
+		return err
+	}
+
+	params := operations.NewDeleteSiteParams()
+	params.SiteID = d.Id()
+	_, err := meta.Operations.DeleteSite(params, meta.AuthInfo)
+	return err
+}
+
+// Deletes the environment variables set on the site level, so that deleting
+// the site doesn't leave them behind in the account.
+func resourceSite_deleteEnvVars(meta *Meta, accountSlug string, siteID string) error {
+	if accountSlug == "" {
+		return nil
+	}
+
+	params := operations.NewGetEnvVarsParams()
+	params.AccountID = accountSlug
+	params.SiteID = &siteID
+	resp, err := meta.Operations.GetEnvVars(params, meta.AuthInfo)
+	if err != nil {
+		if v, ok := err.(*operations.GetEnvVarsDefault); ok && v.Code() == 404 {
+			return nil
+		}
+		return err
+	}
+
+	for _, envVar := range resp.Payload {
+		del := operations.NewDeleteEnvVarParams()
+		del.AccountID = accountSlug
+		del.SiteID = &siteID
+		del.Key = envVar.Key
+		if _, err := meta.Operations.DeleteEnvVar(del, meta.AuthInfo); err != nil {
+			if v, ok := err.(*operations.DeleteEnvVarDefault); ok && v.Code() == 404 {
+				continue
+			}
+			return fmt.Errorf("Error deleting environment variable %s of site %s: %s", envVar.Key, siteID, err)
+		}
+	}
+	return nil
+}
+
+// Imports a site by ID. Arguments that only exist in the configuration are
+// set to their defaults so that an imported site plans without changes.
+func resourceSiteImport(d *schema.ResourceData, metaRaw interface{}) ([]*schema.ResourceData, error) {
+	d.Set("allow_rename", false)
+	d.Set("deletion_protection", false)
+	d.Set("managed_dns", false)
+	d.Set("provision_certificate", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// Returns the account's Netlify DNS zone the site's custom domain falls
+// within, or nil if it uses external DNS. With zones for both a domain and one
+// of its subdomains, e.g. `example.com` and `shop.example.com`, the closest one
+// wins.
+func resourceSite_dnsZone(meta *Meta, site *models.Site) (*models.DNSZone, error) {
+	if site.CustomDomain == "" {
+		return nil, nil
+	}
+
+	params := operations.NewGetDNSZonesParams()
+	params.AccountSlug = &site.AccountSlug
+	resp, err := meta.Operations.GetDNSZones(params, meta.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *models.DNSZone
+	domain := strings.ToLower(site.CustomDomain)
+	for _, zone := range resp.Payload {
+		name := strings.ToLower(zone.Name)
+		if domain != name && !strings.HasSuffix(domain, "."+name) {
+			continue
+		}
+		if match == nil || len(zone.Name) > len(match.Name) {
+			match = zone
+		}
+	}
+
+	return match, nil
+}
+
+// Creates the team's Netlify DNS zone with the given name for the site, unless
+// the team already has it. The zone isn't owned by the site, so it can be
+// imported into a netlify_dns_zone later.
+func resourceSite_ensureDnsZone(meta *Meta, siteID string, accountSlug string, name string) error {
+	zones := operations.NewGetDNSZonesParams()
+	zones.AccountSlug = &accountSlug
+	resp, err := meta.Operations.GetDNSZones(zones, meta.AuthInfo)
+	if err != nil {
+		return err
+	}
+	for _, zone := range resp.Payload {
+		if strings.EqualFold(zone.Name, name) {
+			return nil
+		}
+	}
+
+	params := operations.NewCreateDNSZoneParams()
+	params.DNSZoneParams = &models.DNSZoneSetup{
+		AccountSlug: accountSlug,
+		SiteID:      siteID,
+		Name:        name,
+	}
+	if _, err := meta.Operations.CreateDNSZone(params, meta.AuthInfo); err != nil {
+		return fmt.Errorf("Error creating DNS zone %s: %s", name, err)
+	}
+	return nil
+}
+
+// A zone created by dns_zone must serve the custom domain, and is only useful
+// if Netlify also creates the site's records in it.
+func resourceSite_checkDnsZone(zone string, customDomain string, managedDns bool) error {
+	if zone == "" {
+		return nil
+	}
+	if !managedDns {
+		return errors.New("dns_zone requires managed_dns = true to create the site's records in the zone")
+	}
+
+	domain, name := strings.ToLower(customDomain), strings.ToLower(zone)
+	if domain != name && !strings.HasSuffix(domain, "."+name) {
+		return fmt.Errorf("The custom_domain %q is not within the dns_zone %q", customDomain, zone)
+	}
+	return nil
+}
+
+// Returns a warning if neither the site's config nor a record in the team's
+// Netlify DNS zone points the custom domain at the site. Domains served by
+// another DNS provider can't be checked without a lookup, so they always warn.
+func resourceSite_dnsWarning(meta *Meta, d *schema.ResourceData) diag.Diagnostics {
+	domain := d.Get("custom_domain").(string)
+	if domain == "" || d.Get("managed_dns").(bool) || d.Get("dns_zone").(string) != "" {
+		return nil
+	}
+
+	if zoneID := d.Get("dns_zone_id").(string); zoneID != "" {
+		params := operations.NewGetDNSRecordsParams()
+		params.ZoneID = zoneID
+		resp, err := meta.Operations.GetDNSRecords(params, meta.AuthInfo)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, record := range resp.Payload {
+			if strings.EqualFold(strings.TrimSuffix(record.Hostname, "."), domain) {
+				return nil
+			}
+		}
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("No DNS record points the custom_domain %s at the site", domain),
+			Detail: "Setting custom_domain doesn't create any DNS records. Point the domain at the site with a netlify_dns_zone and netlify_dns_record, " +
+				"managed_dns, or a record at your DNS provider, as described in https://docs.netlify.com/domains-https/custom-domains/.",
+			AttributePath: cty.GetAttrPath("custom_domain"),
+		},
+	}
+}
+
+// Has Netlify create the default DNS records for the site's custom domain.
+func resourceSite_configureDns(meta *Meta, siteID string) error {
+	params := operations.NewConfigureDNSForSiteParams()
+	params.SiteID = siteID
+	resp, err := meta.Operations.ConfigureDNSForSite(params, meta.AuthInfo)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, zone := range resp.Payload {
+		errs = append(errs, zone.Errors...)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("Error configuring DNS for site %s: %s", siteID, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Returns the records Netlify manages for the site in the given zone.
+func resourceSite_managedDnsRecords(meta *Meta, site *models.Site, zone *models.DNSZone) ([]interface{}, error) {
+	params := operations.NewGetDNSRecordsParams()
+	params.ZoneID = zone.ID
+	resp, err := meta.Operations.GetDNSRecords(params, meta.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []interface{}{}
+	for _, record := range resp.Payload {
+		if !record.Managed || record.SiteID != site.ID {
+			continue
+		}
+		records = append(records, map[string]interface{}{
+			"id":       record.ID,
+			"hostname": record.Hostname,
+			"type":     record.Type,
+			"value":    record.Value,
+		})
+	}
+	return records, nil
+}
+
+// Returns the site's TLS certificate status, or nothing if no certificate has
+// been provisioned yet.
+func resourceSite_ssl(meta *Meta, site *models.Site) ([]interface{}, error) {
+	if site.CustomDomain == "" {
+		return []interface{}{}, nil
+	}
+
+	params := operations.NewShowSiteTLSCertificateParams()
+	params.SiteID = site.ID
+	resp, err := meta.Operations.ShowSiteTLSCertificate(params, meta.AuthInfo)
+	if err != nil {
+		if v, ok := err.(*operations.ShowSiteTLSCertificateDefault); ok && v.Code() == 404 {
+			return []interface{}{}, nil
+		}
+
+		return nil, err
+	}
+
+	cert := resp.Payload
+	return []interface{}{
+		map[string]interface{}{
+			"state":      cert.State,
+			"domains":    cert.Domains,
+			"expires_at": cert.ExpiresAt,
+		},
+	}, nil
+}
+
+// Returns the custom domain and aliases of the site that are covered by the
+// given certificate domains, in the order the site lists them. A wildcard
+// like *.example.com covers a single level of subdomains.
+func resourceSite_tlsCoveredDomains(site *models.Site, certDomains []string) []string {
+	covered := []string{}
+	if site.CustomDomain == "" || len(certDomains) == 0 {
+		return covered
+	}
+
+	for _, domain := range append([]string{site.CustomDomain}, site.DomainAliases...) {
+		name := strings.ToLower(domain)
+		for _, certDomain := range certDomains {
+			certDomain = strings.ToLower(certDomain)
+			if name == certDomain {
+				covered = append(covered, domain)
+				break
+			}
+			if i := strings.Index(name, "."); strings.HasPrefix(certDomain, "*.") && i > 0 && name[i:] == certDomain[1:] {
+				covered = append(covered, domain)
+				break
+			}
+		}
+	}
+	return covered
+}
+
+// Only Netlify's own prerendering can be turned on through the API.
+func resourceSite_validatePrerender(value interface{}, path cty.Path) diag.Diagnostics {
+	switch value.(string) {
+	case "", "netlify":
+		return nil
+	}
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid prerender setting.",
+			Detail:        fmt.Sprintf("prerender must be `netlify` or empty, got %q", value.(string)),
+			AttributePath: path,
+		},
+	}
+}
+
+// Asks Netlify to provision a Let's Encrypt certificate for the site's
+// current domains.
+func resourceSite_provisionCertificate(meta *Meta, siteID string) error {
+	params := operations.NewProvisionSiteTLSCertificateParams()
+	params.SiteID = siteID
+	_, err := meta.Operations.ProvisionSiteTLSCertificate(params, meta.AuthInfo)
+	if err != nil {
+		return fmt.Errorf("Error provisioning a Let's Encrypt certificate for site %s, check that the site's domains resolve to Netlify: %s", siteID, err)
+	}
+	return nil
+}
+
+// Flattens the site's capabilities into strings, since their values mix
+// toggles, numbers and nested settings.
+func resourceSite_capabilities(capabilities map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for name, value := range capabilities {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			result[name] = v
+		case bool, float64, json.Number:
+			result[name] = fmt.Sprint(v)
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			result[name] = string(encoded)
+		}
+	}
+	return result
+}
+
+// Returns the plan of the team with the given slug if the provider is
+// configured to read it. Teams are cached, so sites sharing a team only look
+// it up once per run.
+func resourceSite_accountType(meta *Meta, slug string) (string, error) {
+	if !meta.readAccountTypes || slug == "" {
+		return "", nil
+	}
+
+	account, err := meta.Account(slug)
+	if err != nil {
+		return "", err
+	}
+	return account.Type, nil
+}
+
+// Returns the state of the site's most recent deploy if the provider is
+// configured to read it, or "" if there is none.
+func resourceSite_lastDeployState(meta *Meta, siteID string) (string, error) {
+	if !meta.readLastDeployStates {
+		return "", nil
+	}
+
+	page, perPage := int32(1), int32(1)
+	params := operations.NewListSiteDeploysParams()
+	params.SiteID = siteID
+	params.Page = &page
+	params.PerPage = &perPage
+	resp, err := meta.Operations.ListSiteDeploys(params, meta.AuthInfo)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Payload) == 0 {
+		return "", nil
+	}
+	return resp.Payload[0].State, nil
+}
+
+// Lists the site's build hooks without their URLs, which trigger builds and
+// are therefore secret, if the provider is configured to read them.
+func resourceSite_buildHooks(meta *Meta, siteID string) ([]interface{}, error) {
+	if !meta.readBuildHooks {
+		return []interface{}{}, nil
+	}
+
+	params := operations.NewListSiteBuildHooksParams()
+	params.SiteID = siteID
+	resp, err := meta.Operations.ListSiteBuildHooks(params, meta.AuthInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]interface{}, 0, len(resp.Payload))
+	for _, hook := range resp.Payload {
+		hooks = append(hooks, map[string]interface{}{
+			"id":     hook.ID,
+			"title":  hook.Title,
+			"branch": hook.Branch,
+		})
+	}
+	return hooks, nil
+}
+
+// Flattens the site's published deploy, if it has one.
+func resourceSite_publishedDeploy(site *models.Site) []interface{} {
+	deploy := site.PublishedDeploy
+	if deploy == nil || deploy.ID == "" {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"id":         deploy.ID,
+			"commit_ref": deploy.CommitRef,
+			"branch":     deploy.Branch,
+			"state":      deploy.State,
+			"deploy_url": deploy.DeployURL,
+		},
+	}
+}
+
+// Checks a planned site change. It:
+//   - guards against accidentally renaming a site, which changes its
+//     subdomain and can break existing links,
+//   - rejects renaming and moving a site at the same time,
+//   - rejects a functions directory inside the publish directory,
+//   - rejects a dns_zone that doesn't serve the custom domain,
+//   - rejects forcing HTTPS without a certificate, and
+//   - recreates the site when its git provider changes.
+func resourceSiteCustomizeDiff(c context.Context, d *schema.ResourceDiff, metaRaw interface{}) error {
+	if d.Id() != "" && d.HasChange("name") && d.HasChange("account_slug") {
+		return errors.New("Changing both the name and the account_slug of a site in one apply is not supported; rename the site first, then move it to the new team in a separate apply")
+	}
+
+	if d.Id() != "" && d.HasChange("name") && !d.Get("allow_rename").(bool) {
+		return errors.New("Changing the name of a site changes its netlify.app subdomain; set allow_rename = true to confirm the rename")
+	}
+
+	if err := resourceSite_checkFunctionsDir(d.Get("repo.0.dir").(string), d.Get("repo.0.functions_dir").(string)); err != nil {
+		return err
+	}
+
+	if err := resourceSite_checkDnsZone(d.Get("dns_zone").(string), d.Get("custom_domain").(string), d.Get("managed_dns").(bool)); err != nil {
+		return err
+	}
+
+	if d.HasChanges("force_ssl", "custom_domain", "provision_certificate") {
+		hasCertificate := len(d.Get("ssl").([]interface{})) > 0 && !d.HasChange("custom_domain")
+		if err := resourceSite_checkForceSsl(d.Get("force_ssl").(bool), d.Get("custom_domain").(string), d.Get("provision_certificate").(bool), hasCertificate); err != nil {
+			return err
+		}
+	}
+
+	// UpdateSite can't move a site between git providers cleanly. Removing the
+	// repo block entirely is still handled in place by unlinking the repo.
+	if d.HasChange("repo.0.provider") {
+		old, new := d.GetChange("repo.0.provider")
+		if old.(string) != "" && new.(string) != "" {
+			if err := d.ForceNew("repo.0.provider"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Forcing HTTPS on a custom domain without a certificate makes the site
+// unreachable, since browsers get redirected to a domain Netlify can't serve
+// securely. The netlify.app subdomain always has a certificate.
+func resourceSite_checkForceSsl(forceSsl bool, customDomain string, provisionCertificate bool, hasCertificate bool) error {
+	if !forceSsl || customDomain == "" || provisionCertificate || hasCertificate {
+		return nil
+	}
+
+	return fmt.Errorf("force_ssl would redirect %s to HTTPS before it has a certificate; set provision_certificate = true, "+
+		"or enable force_ssl in a later apply once a netlify_ssl_certificate has been issued", customDomain)
+}
+
+// Netlify rejects a custom_domain that another site already uses with a 409
+// or 422 whose message is easy to miss. Rewrites that error into one that
+// names the domain, keeping the API's message since it may name the other
+// site. Only errors whose message names the custom domain are rewritten, so
+// conflicts over domain_aliases and any other error are returned unchanged.
+func resourceSite_domainConflict(d *schema.ResourceData, err error) error {
+	domain := d.Get("custom_domain").(string)
+	if domain == "" {
+		return err
+	}
+
+	var code int
+	var payload *models.Error
+	switch v := err.(type) {
+	case *operations.CreateSiteDefault:
+		code, payload = v.Code(), v.Payload
+	case *operations.CreateSiteInTeamDefault:
+		code, payload = v.Code(), v.Payload
+	case *operations.UpdateSiteDefault:
+		code, payload = v.Code(), v.Payload
+	default:
+		return err
+	}
+
+	if code != 409 && code != 422 {
+		return err
+	}
+
+	if payload == nil || !resourceSite_mentionsDomain(payload.Message, domain) {
+		return err
+	}
+	return fmt.Errorf("The custom_domain %q is already in use by another Netlify site; remove it from that site first: %s", domain, payload.Message)
+}
+
+// Reports whether message names domain as a whole, so that a message about
+// www.example.com doesn't count as naming example.com.
+func resourceSite_mentionsDomain(message string, domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	words := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '*')
+	})
+	for _, word := range words {
+		if strings.TrimSuffix(word, ".") == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// Netlify deploys everything in the publish directory as static files, so
+// functions inside it end up published as source and the deploy fails.
+func resourceSite_checkFunctionsDir(dir string, functionsDir string) error {
+	clean := func(p string) string {
+		return strings.Trim(path.Clean("/"+p), "/")
+	}
+
+	publish, functions := clean(dir), clean(functionsDir)
+	if publish == "" || functions == "" {
+		return nil
+	}
+
+	if functions == publish || strings.HasPrefix(functions, publish+"/") {
+		return fmt.Errorf("repo.0.functions_dir (%s) must not be inside the publish directory repo.0.dir (%s)", functionsDir, dir)
+	}
+	return nil
+}
+
+// Applies the build settings that can't be expressed through SiteSetup,
+// either because re-enabling or clearing them requires sending an explicit
+// `false` or `""`, or because models.RepoInfo doesn't have the field.
+func resourceSite_patchBuildSettings(d *schema.ResourceData, meta *Meta) error {
+	// null clears a setting
+	orNull := func(key string) interface{} {
+		if v := d.Get(key).(string); v != "" {
+			return v
+		}
+		return nil
+	}
+
+	settings := map[string]interface{}{
+		"stop_builds":   !d.Get("builds_enabled").(bool),
+		"skip_prs":      !d.Get("deploy_previews").(bool),
+		"private_logs":  d.Get("private_build_logs").(bool),
+		"ignore":        orNull("repo.0.ignore_command"),
+		"package_path":  orNull("repo.0.package_path"),
+		"functions_dir": orNull("repo.0.functions_dir"),
+	}
+
+	// An empty command means there is no build step, so unlike the settings
+	// above it is sent as is rather than cleared. public_repo is sent along
+	// with it since SiteSetup can't turn it off, but only when it's managed:
+	// otherwise the computed value would overwrite the flag Netlify detected.
+	if _, ok := d.GetOk("repo"); ok {
+		settings["cmd"] = d.Get("repo.0.command").(string)
+		if resourceSite_repoConfigured(d, "public_repo") || (!d.IsNewResource() && d.HasChange("repo.0.public_repo")) {
+			settings["public_repo"] = d.Get("repo.0.public_repo").(bool)
+		}
+
+		// framework is computed, so without an override it holds the preset
+		// Netlify detected. It is only sent when configured, since sending the
+		// detected preset back would keep Netlify from detecting another one.
+		if resourceSite_repoConfigured(d, "framework") {
+			settings["framework"] = d.Get("repo.0.framework").(string)
+		}
+
+		// Sending an empty env would wipe variables managed outside of Terraform
+		if _, ok := d.GetOk("repo.0.build_env"); ok || d.HasChange("repo.0.build_env") {
+			old, new := d.GetChange("repo.0.build_env")
+			settings["env"] = resourceSite_buildEnvPatch(old.(map[string]interface{}), new.(map[string]interface{}))
+		}
+	}
+
+	return patchSite(meta, d.Id(), map[string]interface{}{
+		"build_settings": settings,
+	})
+}
+
+// Returns whether the attribute of repo.0 is set in the configuration, which
+// GetOk can't tell for a false bool or a computed attribute.
+func resourceSite_repoConfigured(d *schema.ResourceData, attr string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute("repo") {
+		return false
+	}
+	repos := config.GetAttr("repo")
+	if repos.IsNull() || !repos.IsKnown() || repos.LengthInt() == 0 {
+		return false
+	}
+	repo := repos.Index(cty.NumberIntVal(0))
+	if repo.IsNull() || !repo.IsKnown() || !repo.Type().HasAttribute(attr) {
+		return false
+	}
+	return !repo.GetAttr(attr).IsNull()
+}
+
+// Returns the build environment to patch: the configured variables, with the
+// ones removed from the configuration set to null so they are cleared.
+func resourceSite_buildEnvPatch(old map[string]interface{}, new map[string]interface{}) map[string]interface{} {
+	env := make(map[string]interface{}, len(old)+len(new))
+	for k := range old {
+		env[k] = nil
+	}
+	for k, v := range new {
+		env[k] = v
+	}
+	return env
+}
+
+// Returns the build_env to store in state. Until build_env is set, the legacy
+// build environment is left unmanaged, since it may hold variables set in the
+// UI or through other tools.
+func resourceSite_buildEnv(d *schema.ResourceData, env map[string]string) map[string]interface{} {
+	result := map[string]interface{}{}
+	if len(d.Get("repo.0.build_env").(map[string]interface{})) == 0 {
+		return result
+	}
+
+	for k, v := range env {
+		result[k] = v
+	}
+	return result
+}
+
+// Returns the SiteSetup structure that can be used for creation or updating.
+func resourceSite_setupStruct(d *schema.ResourceData, meta *Meta) (*models.SiteSetup, error) {
+	// Unlike the other fields domain_aliases is always sent. UpdateSite replaces
+	// the whole list, so aliases removed from the configuration are dropped and
+	// an empty list clears them.
+	aliases := []string{}
+	for _, alias := range d.Get("domain_aliases").(*schema.Set).List() {
+		aliases = append(aliases, alias.(string))
+	}
+
+	result := &models.SiteSetup{
+		Site: models.Site{
+			Name:          d.Get("name").(string),
+			CustomDomain:  d.Get("custom_domain").(string),
+			DomainAliases: aliases,
+			Prerender:     d.Get("prerender").(string),
+		},
+	}
+
+	// If we have a repo config, then configure that
+	if v, ok := d.GetOk("repo"); ok {
+		vL := v.([]interface{})
+		repo := vL[0].(map[string]interface{})
+
+		result.Repo = &models.RepoInfo{
+			Cmd:            repo["command"].(string),
 			DeployKeyID:    repo["deploy_key_id"].(string),
 			Dir:            repo["dir"].(string),
+			FunctionsDir:   repo["functions_dir"].(string),
 			Provider:       repo["provider"].(string),
 			RepoPath:       repo["repo_path"].(string),
 			RepoBranch:     repo["repo_branch"].(string),
+			ID:             int64(repo["repo_id"].(int)),
+			PublicRepo:     repo["public_repo"].(bool),
 			InstallationID: int64(repo["installation_id"].(int)),
 		}
+
+		// The production branch always has to be part of the allowed branches
+		if v, ok := d.GetOk("repo.0.allowed_branches"); ok {
+			branches := []string{result.Repo.RepoBranch}
+			for _, b := range v.(*schema.Set).List() {
+				if b.(string) != result.Repo.RepoBranch {
+					branches = append(branches, b.(string))
+				}
+			}
+			result.Repo.AllowedBranches = branches
+		}
+
+		// Resolve the deploy key from its public key if no ID was given. Since
+		// deploy_key_id is computed it still holds the old key's ID when the
+		// public key is rotated, so a changed public key takes precedence.
+		publicKey := repo["deploy_key_public_key"].(string)
+		if publicKey != "" && (result.Repo.DeployKeyID == "" || d.HasChange("repo.0.deploy_key_public_key")) {
+			id, err := resourceSite_findDeployKey(meta, publicKey)
+			if err != nil {
+				return nil, err
+			}
+			result.Repo.DeployKeyID = id
+		}
 	}
 
-	return result
+	return result, nil
+}
+
+// Returns the provider, path and branch of the linked repo. Missing ones are
+// derived from the repo URL where possible, and otherwise kept from state.
+func resourceSite_repoIdentity(d *schema.ResourceData, settings *models.RepoInfo) (string, string, string) {
+	provider, repoPath, repoBranch := settings.Provider, settings.RepoPath, settings.RepoBranch
+
+	if u, err := url.Parse(settings.RepoURL); err == nil && u.Host != "" {
+		if provider == "" {
+			provider = repoProviders[strings.TrimPrefix(strings.ToLower(u.Host), "www.")]
+		}
+		if repoPath == "" {
+			repoPath = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		}
+	}
+
+	if provider == "" {
+		provider = d.Get("repo.0.provider").(string)
+	}
+	if repoPath == "" {
+		repoPath = d.Get("repo.0.repo_path").(string)
+	}
+	if repoBranch == "" {
+		repoBranch = d.Get("repo.0.repo_branch").(string)
+	}
+	return provider, repoPath, repoBranch
+}
+
+// The git providers Netlify links repos from, by the host of their repo URLs.
+var repoProviders = map[string]string{
+	"github.com":    "github",
+	"gitlab.com":    "gitlab",
+	"bitbucket.org": "bitbucket",
+}
+
+// Returns the allowed branches without the production branch, which Netlify
+// always includes, unless it was explicitly configured.
+func resourceSite_allowedBranches(d *schema.ResourceData, settings *models.RepoInfo) []interface{} {
+	configured := map[string]bool{}
+	if v, ok := d.GetOk("repo.0.allowed_branches"); ok {
+		for _, b := range v.(*schema.Set).List() {
+			configured[b.(string)] = true
+		}
+	}
+
+	branches := []interface{}{}
+	for _, b := range settings.AllowedBranches {
+		if b != settings.RepoBranch || configured[b] {
+			branches = append(branches, b)
+		}
+	}
+	return branches
+}
+
+// Looks up the installation ID for the repo when git_provider_uses_installation
+// is set but no installation_id is given.
+func resourceSite_resolveInstallation(d *schema.ResourceData, meta *Meta, setup *models.SiteSetup) error {
+	if setup.Repo == nil || setup.Repo.InstallationID != 0 || !d.Get("repo.0.git_provider_uses_installation").(bool) {
+		return nil
+	}
+
+	sites, err := listSites(meta, meta.accountSlug(d.Get("account_slug").(string)))
+	if err != nil {
+		return err
+	}
+
+	id := resourceSite_findInstallationID(sites, setup.Repo.Provider, setup.Repo.RepoPath)
+	if id == 0 {
+		return fmt.Errorf("No %s app installation found for %s; set installation_id, or connect a site from the same owner through the Netlify UI first", setup.Repo.Provider, setup.Repo.RepoPath)
+	}
+	setup.Repo.InstallationID = id
+	return nil
+}
+
+// Returns the installation ID of the first site connected to a repo of the
+// same provider and owner, or 0 if there is none. App installations belong to
+// an owner, so any of its repos' sites will do.
+func resourceSite_findInstallationID(sites []*models.Site, provider string, repoPath string) int64 {
+	owner := strings.SplitN(repoPath, "/", 2)[0]
+	for _, site := range sites {
+		settings := site.BuildSettings
+		if settings == nil || settings.InstallationID == 0 || settings.Provider != provider {
+			continue
+		}
+		if strings.EqualFold(strings.SplitN(settings.RepoPath, "/", 2)[0], owner) {
+			return settings.InstallationID
+		}
+	}
+	return 0
+}
+
+// Returns the ID of the deploy key with the given public key.
+func resourceSite_findDeployKey(meta *Meta, publicKey string) (string, error) {
+	resp, err := meta.Operations.ListDeployKeys(
+		operations.NewListDeployKeysParams(), meta.AuthInfo)
+	if err != nil {
+		return "", err
+	}
+
+	publicKey = strings.TrimSpace(publicKey)
+	for _, key := range resp.Payload {
+		if strings.TrimSpace(key.PublicKey) == publicKey {
+			return key.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("No deploy key found with public key %q", publicKey)
 }
@@ -0,0 +1,286 @@
+package netlify
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+	"github.com/netlify/open-api/v2/go/porcelain"
+	porcelainContext "github.com/netlify/open-api/v2/go/porcelain/context"
+)
+
+// Deploys a pre-built directory or zip archive to a site without going
+// through a git build. Files are deployed by digest, so only files Netlify
+// doesn't already have are uploaded. Netlify keeps every deploy in the site's
+// history, so destroying the resource only removes it from state.
+func resourceSiteDeploy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSiteDeployCreate,
+		ReadContext:   resourceSiteDeployRead,
+		DeleteContext: resourceSiteDeployDelete,
+		CustomizeDiff: resourceSiteDeployCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"dir": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dir", "zip_file"},
+				Description:  "The directory to deploy.",
+			},
+
+			"zip_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dir", "zip_file"},
+				Description:  "The zip archive to deploy. It is extracted to a temporary directory and deployed the same way as `dir`.",
+			},
+
+			"title": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"draft": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Set to `true` to create a draft deploy that doesn't get published on the site.",
+			},
+
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "A hash of the deployed files. A new deploy is created whenever the files change.",
+			},
+
+			"deploy_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"deploy_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSiteDeployCreate(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+
+	dir := d.Get("dir").(string)
+	if v, ok := d.GetOk("zip_file"); ok {
+		tmp, err := os.MkdirTemp("", "terraform-provider-netlify-deploy")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer os.RemoveAll(tmp)
+
+		if err := resourceSiteDeploy_extractZip(v.(string), tmp); err != nil {
+			return diag.Errorf("Error extracting %s: %s", v.(string), err)
+		}
+		dir = tmp
+	}
+
+	hash, err := resourceSiteDeploy_contentHash(d.Get("dir").(string), d.Get("zip_file").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	deployCtx := porcelainContext.WithLogger(porcelainContext.WithAuthInfo(ctx, meta.AuthInfo), newPorcelainLogger(ctx))
+	deploy, err := meta.Netlify.DeploySite(deployCtx, porcelain.DeployOptions{
+		SiteID:  d.Get("site_id").(string),
+		Dir:     dir,
+		Title:   d.Get("title").(string),
+		IsDraft: d.Get("draft").(bool),
+	})
+	if err != nil {
+		return diag.Errorf("Error deploying to site %s: %s", d.Get("site_id").(string), err)
+	}
+
+	d.SetId(deploy.ID)
+	d.Set("content_hash", hash)
+	return resourceSiteDeployRead(ctx, d, metaRaw)
+}
+
+func resourceSiteDeployRead(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	meta := metaRaw.(*Meta)
+	params := operations.NewGetSiteDeployParams()
+	params.SiteID = d.Get("site_id").(string)
+	params.DeployID = d.Id()
+	resp, err := meta.Operations.GetSiteDeploy(params, meta.AuthInfo)
+	if err != nil {
+		// If it is a 404 the deploy or its site was removed remotely
+		if v, ok := err.(*operations.GetSiteDeployDefault); ok && v.Code() == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+
+	deploy := resp.Payload
+	d.Set("deploy_id", deploy.ID)
+	d.Set("deploy_url", deploy.DeployURL)
+	d.Set("state", deploy.State)
+
+	return nil
+}
+
+func resourceSiteDeployDelete(ctx context.Context, d *schema.ResourceData, metaRaw interface{}) diag.Diagnostics {
+	return nil
+}
+
+// Plans a new deploy when the deployed files change. A directory that doesn't
+// exist yet, e.g. because another resource builds it, is hashed on apply.
+func resourceSiteDeployCustomizeDiff(c context.Context, d *schema.ResourceDiff, metaRaw interface{}) error {
+	dir, zipFile := d.Get("dir").(string), d.Get("zip_file").(string)
+	if dir == "" && zipFile == "" {
+		return nil
+	}
+
+	hash, err := resourceSiteDeploy_contentHash(dir, zipFile)
+	if os.IsNotExist(err) {
+		return d.SetNewComputed("content_hash")
+	}
+	if err != nil {
+		return err
+	}
+
+	if d.Id() != "" && d.Get("content_hash").(string) == hash {
+		return nil
+	}
+	return d.SetNew("content_hash", hash)
+}
+
+// Returns a hash of the zip file if one is given, or else of the paths and
+// contents of every file in the directory.
+func resourceSiteDeploy_contentHash(dir string, zipFile string) (string, error) {
+	h := sha1.New()
+	if zipFile != "" {
+		f, err := os.Open(zipFile)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return "", err
+	}
+
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return "", err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Extracts the zip archive into dir, rejecting entries that would end up
+// outside of it.
+func resourceSiteDeploy_extractZip(zipFile string, dir string) error {
+	r, err := zip.OpenReader(zipFile)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		path := filepath.Join(dir, f.Name)
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("Invalid file path %q in archive", f.Name)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := resourceSiteDeploy_extractFile(f, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceSiteDeploy_extractFile(f *zip.File, path string) error {
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
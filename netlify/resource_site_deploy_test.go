@@ -0,0 +1,181 @@
+package netlify
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSiteDeploy_dir(t *testing.T) {
+	resourceName := "netlify_site_deploy.test"
+	dir := t.TempDir()
+	writeFile := func(content string) error {
+		return os.WriteFile(filepath.Join(dir, "index.html"), []byte(content), 0644)
+	}
+	if err := writeFile("<h1>First</h1>"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var deployID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteDeployConfig, dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "deploy_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "deploy_url"),
+					resource.TestCheckResourceAttrSet(resourceName, "content_hash"),
+					func(s *terraform.State) error {
+						deployID = s.RootModule().Resources[resourceName].Primary.ID
+						return writeFile("<h1>Second</h1>")
+					},
+				),
+				ExpectNonEmptyPlan: true,
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteDeployConfig, dir),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[resourceName].Primary.ID == deployID {
+							return fmt.Errorf("expected changed files to create a new deploy")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// The API has no error page settings, so a single page app's fallback is a
+// _redirects rule deployed along with the files.
+func TestAccSiteDeploy_spaFallback(t *testing.T) {
+	resourceName := "netlify_site_deploy.test"
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html": "<h1>App</h1>",
+		"_redirects": "/*    /index.html    200\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteDeployConfig, dir),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteDeployServes(resourceName, "/some/client/route", "<h1>App</h1>"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceSiteDeploy_contentHash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "assets"), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("index"), 0644)
+	os.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("app"), 0644)
+
+	first, err := resourceSiteDeploy_contentHash(dir, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	second, _ := resourceSiteDeploy_contentHash(dir, "")
+	if first != second {
+		t.Errorf("expected the hash to be stable, got %q and %q", first, second)
+	}
+
+	os.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("changed"), 0644)
+	if changed, _ := resourceSiteDeploy_contentHash(dir, ""); changed == first {
+		t.Errorf("expected changed contents to change the hash")
+	}
+
+	if _, err := resourceSiteDeploy_contentHash(filepath.Join(dir, "missing"), ""); !os.IsNotExist(err) {
+		t.Errorf("expected a missing directory to be reported, got %v", err)
+	}
+}
+
+func TestResourceSiteDeploy_extractZip(t *testing.T) {
+	dir := t.TempDir()
+	writeZip := func(name string) string {
+		path := filepath.Join(dir, "site.zip")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		w := zip.NewWriter(f)
+		entry, _ := w.Create(name)
+		entry.Write([]byte("index"))
+		w.Close()
+		f.Close()
+		return path
+	}
+
+	out := filepath.Join(dir, "out")
+	if err := resourceSiteDeploy_extractZip(writeZip("public/index.html"), out); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if b, err := os.ReadFile(filepath.Join(out, "public", "index.html")); err != nil || string(b) != "index" {
+		t.Errorf("expected the archive to be extracted, got %q (%v)", b, err)
+	}
+
+	err := resourceSiteDeploy_extractZip(writeZip("../escape.html"), filepath.Join(dir, "escape"))
+	if err == nil || !strings.Contains(err.Error(), "Invalid file path") {
+		t.Errorf("expected paths outside the directory to be rejected, got %v", err)
+	}
+}
+
+// Checks that the deploy serves body at path, waiting for Netlify to finish
+// processing the deploy.
+func testAccCheckSiteDeployServes(resourceName string, path string, body string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		url := s.RootModule().Resources[resourceName].Primary.Attributes["deploy_url"] + path
+		return resource.Retry(2*time.Minute, func() *resource.RetryError {
+			resp, err := http.Get(url)
+			if err != nil {
+				return resource.RetryableError(err)
+			}
+			defer resp.Body.Close()
+
+			content, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return resource.RetryableError(err)
+			}
+			if resp.StatusCode != http.StatusOK || !strings.Contains(string(content), body) {
+				return resource.RetryableError(fmt.Errorf("Expected %s to serve %q, got %d: %s", url, body, resp.StatusCode, content))
+			}
+			return nil
+		})
+	}
+}
+
+var testAccSiteDeployConfig = `
+resource "netlify_site" "test" {}
+
+resource "netlify_site_deploy" "test" {
+	site_id = netlify_site.test.id
+	dir = "%s"
+}
+`
@@ -1,10 +1,24 @@
 package netlify
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/netlify/open-api/v2/go/models"
 	"github.com/netlify/open-api/v2/go/plumbing/operations"
@@ -23,12 +37,40 @@ func TestAccSite_basic(t *testing.T) {
 				Config: testAccSiteConfig_repo,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttrSet(resourceName, "repo.0.deploy_hook"),
+					resource.TestCheckResourceAttrSet(resourceName, "repo.0.repo_id"),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.repo_url", "https://github.com/mitchellh/fogli"),
+					resource.TestCheckResourceAttr(resourceName, "dns_managed_by_netlify", "false"),
+					resource.TestCheckResourceAttr(resourceName, "dns_zone_id", ""),
+					resource.TestCheckResourceAttr(resourceName, "ssl.#", "0"),
+					resource.TestCheckResourceAttrSet(resourceName, "capabilities.%"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccSite_import(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig_repo,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccSite_disappears(t *testing.T) {
 	var site models.Site
 
@@ -36,7 +78,7 @@ func TestAccSite_disappears(t *testing.T) {
 		meta := testAccProvider.Meta().(*Meta)
 		params := operations.NewDeleteSiteParams()
 		params.SiteID = site.ID
-		_, err := meta.Netlify.Operations.DeleteSite(params, meta.AuthInfo)
+		_, err := meta.Operations.DeleteSite(params, meta.AuthInfo)
 		return err
 	}
 
@@ -90,72 +132,2167 @@ func TestAccSite_updateName(t *testing.T) {
 	})
 }
 
-func testAccCheckSiteExists(n string, site *models.Site) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		rs, ok := s.RootModule().Resources[n]
-		if !ok {
-			return fmt.Errorf("Not Found: %s", n)
-		}
+func TestAccSite_preventRename(t *testing.T) {
+	resourceName := "netlify_site.test"
+	siteName := fmt.Sprintf("test-%s", RandStringBytes(6))
 
-		if rs.Primary.ID == "" {
-			return fmt.Errorf("No membership ID is set")
-		}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_rename, siteName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", siteName),
+				),
+			},
 
-		meta := testAccProvider.Meta().(*Meta)
-		params := operations.NewGetSiteParams()
-		params.SiteID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
-		if err != nil {
-			return err
-		}
+			{
+				Config:      fmt.Sprintf(testAccSiteConfig_rename, siteName+"-renamed"),
+				ExpectError: regexp.MustCompile("allow_rename"),
+			},
+		},
+	})
+}
 
-		*site = *resp.Payload
-		return nil
-	}
+func TestAccSite_buildsEnabled(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_buildsEnabled, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has stopped builds", func() bool {
+						return site.BuildSettings.StopBuilds
+					}),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_buildsEnabled, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has resumed builds", func() bool {
+						return !site.BuildSettings.StopBuilds
+					}),
+				),
+			},
+		},
+	})
 }
 
-func testAccCheckSiteDestroy(s *terraform.State) error {
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "netlify_site" {
-			continue
-		}
+func TestAccSite_buildsStoppedRemotely(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
 
+	stopBuilds := func(*terraform.State) error {
 		meta := testAccProvider.Meta().(*Meta)
-		params := operations.NewGetSiteParams()
-		params.SiteID = rs.Primary.ID
-		resp, err := meta.Netlify.Operations.GetSite(params, meta.AuthInfo)
-		if err == nil && resp.Payload != nil {
-			return fmt.Errorf("Site still exists: %s", rs.Primary.ID)
-		}
+		return patchSite(meta, site.ID, map[string]interface{}{
+			"build_settings": map[string]interface{}{"stop_builds": true},
+		})
+	}
 
-		if err != nil {
-			if v, ok := err.(*operations.GetSiteDefault); ok && v.Code() == 404 {
-				return nil
-			}
-		}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_buildsEnabled, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					stopBuilds,
+				),
+				ExpectNonEmptyPlan: true,
+			},
 
-		return err
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_buildsEnabled, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "builds_enabled", "true"),
+					testAccAssert("has resumed builds", func() bool {
+						return !site.BuildSettings.StopBuilds
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_publicRepoChangedRemotely(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	makePrivate := func(*terraform.State) error {
+		meta := testAccProvider.Meta().(*Meta)
+		return patchSite(meta, site.ID, map[string]interface{}{
+			"build_settings": map[string]interface{}{"public_repo": false},
+		})
 	}
 
-	return nil
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig_publicRepo,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.public_repo", "true"),
+					makePrivate,
+				),
+				ExpectNonEmptyPlan: true,
+			},
+
+			{
+				Config: testAccSiteConfig_publicRepo,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.public_repo", "true"),
+					testAccAssert("is public again", func() bool {
+						return site.BuildSettings.PublicRepo
+					}),
+				),
+			},
+		},
+	})
 }
 
-var testAccSiteConfig = `
-resource "netlify_site" "test" {}
-`
+// A site forcing HTTPS outside of Terraform keeps doing so while force_ssl
+// isn't configured.
+func TestAccSite_forceSslUnmanaged(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
 
-var testAccSiteConfig_repo = `
-resource "netlify_site" "test" {
-	repo {
-		provider = "github"
-		repo_path = "mitchellh/fogli"
-		repo_branch = "master"
+	forceSsl := func(*terraform.State) error {
+		meta := testAccProvider.Meta().(*Meta)
+		return patchSite(meta, site.ID, map[string]interface{}{"force_ssl": true})
 	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					forceSsl,
+				),
+			},
+
+			{
+				Config:   testAccSiteConfig,
+				PlanOnly: true,
+			},
+		},
+	})
 }
-`
 
-var testAccSiteConfig_updateName = `
-resource "netlify_site" "test" {
-	name = "%s"
+func TestAccSite_ignoreCommand(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_ignoreCommand, "git diff --quiet HEAD^ HEAD -- site/"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.ignore_command", "git diff --quiet HEAD^ HEAD -- site/"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_ignoreCommand, "exit 1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.ignore_command", "exit 1"),
+				),
+			},
+
+			{
+				Config: testAccSiteConfig_repo,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.ignore_command", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_clearCommand(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_command, "npm run build"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.command", "npm run build"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_command, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.command", ""),
+					testAccAssert("has no build command", func() bool {
+						return site.BuildSettings.Cmd == ""
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_domainAliases(t *testing.T) {
+	resourceName := "netlify_site.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_domainAliases, domain, `"www.${local.domain}"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "domain_aliases.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "domain_aliases.*", "www."+domain),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_domainAliases, domain, "", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "domain_aliases.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_forceSslWithoutCertificate(t *testing.T) {
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccSiteConfig_forceSsl, domain),
+				ExpectError: regexp.MustCompile("before it has a certificate"),
+			},
+		},
+	})
+}
+
+func TestAccSite_clearCustomDomain(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+	domain := fmt.Sprintf("www.tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_customDomain, fmt.Sprintf("%q", domain)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "custom_domain", domain),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_customDomain, "null"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "custom_domain", ""),
+					testAccAssert("domain was removed remotely", func() bool {
+						return site.CustomDomain == ""
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_removeDomainAlias(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_domainAliases, domain, `"www.${local.domain}", "app.${local.domain}", "docs.${local.domain}"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has three aliases", func() bool {
+						return len(site.DomainAliases) == 3
+					}),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_domainAliases, domain, `"www.${local.domain}", "docs.${local.domain}"`, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "domain_aliases.#", "2"),
+					testAccAssert("removed alias was dropped remotely", func() bool {
+						sort.Strings(site.DomainAliases)
+						return reflect.DeepEqual(site.DomainAliases, []string{"docs." + domain, "www." + domain})
+					}),
+				),
+			},
+		},
+	})
+}
+
+// Needs a domain whose `www` subdomain already points at Netlify, since Let's
+// Encrypt only issues certificates for domains that resolve to the site.
+func TestAccSite_provisionCertificate(t *testing.T) {
+	domain := os.Getenv("NETLIFY_TEST_TLS_DOMAIN")
+	if domain == "" {
+		t.Skip("NETLIFY_TEST_TLS_DOMAIN must be set to test provisioning certificates")
+	}
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_domainAliases, domain, "", true),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_domainAliases, domain, `"www.${local.domain}"`, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr(resourceName, "domain_aliases.*", "www."+domain),
+					resource.TestCheckTypeSetElemAttr(resourceName, "ssl.0.domains.*", "www."+domain),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tls_covered_domains.*", "www."+domain),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_prerender(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_prerender, "netlify"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "prerender", "netlify"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_prerender, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "prerender", ""),
+					testAccAssert("has prerendering turned off", func() bool {
+						return site.Prerender == ""
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_deletionProtection(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_deletionProtection, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+				),
+			},
+
+			{
+				Config:      fmt.Sprintf(testAccSiteConfig_deletionProtection, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion_protection"),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_deletionProtection, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_managedDns(t *testing.T) {
+	resourceName := "netlify_site.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_managedDns, domain, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "managed_dns_records.#", "0"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_managedDns, domain, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "dns_managed_by_netlify", "true"),
+					resource.TestCheckResourceAttrPair(resourceName, "dns_zone_id", "netlify_dns_zone.test", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "managed_dns_records.0.id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_dnsZone(t *testing.T) {
+	resourceName := "netlify_site.test"
+	domain := fmt.Sprintf("tf-test-%s.com", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckDnsZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_dnsZone, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "dns_managed_by_netlify", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "dns_zone_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "dns_zone_name_servers.0"),
+					resource.TestCheckResourceAttrSet(resourceName, "managed_dns_records.0.id"),
+				),
+			},
+
+			// The zone can be moved into its own resource later
+			{
+				Config:             fmt.Sprintf(testAccSiteConfig_dnsZoneSeparate, domain),
+				ResourceName:       "netlify_dns_zone.test",
+				ImportState:        true,
+				ImportStatePersist: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return s.RootModule().Resources[resourceName].Primary.Attributes["dns_zone_id"], nil
+				},
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_dnsZoneSeparate, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "dns_zone_id", "netlify_dns_zone.test", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "dns_zone_name_servers.#", "netlify_dns_zone.test", "name_servers.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceSite_ensureDnsZone(t *testing.T) {
+	var created []*models.DNSZoneSetup
+	ops := &testOperations{
+		getDNSZones: func(params *operations.GetDNSZonesParams) (*operations.GetDNSZonesOK, error) {
+			if *params.AccountSlug != "team" {
+				t.Errorf("expected the zones of the site's team to be listed, got %s", *params.AccountSlug)
+			}
+			return &operations.GetDNSZonesOK{Payload: []*models.DNSZone{{ID: "existing", Name: "Example.com"}}}, nil
+		},
+		createDNSZone: func(params *operations.CreateDNSZoneParams) (*operations.CreateDNSZoneCreated, error) {
+			created = append(created, params.DNSZoneParams)
+			return &operations.CreateDNSZoneCreated{Payload: &models.DNSZone{ID: "new"}}, nil
+		},
+	}
+
+	if err := resourceSite_ensureDnsZone(testMeta(ops), "site", "team", "example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(created) != 0 {
+		t.Errorf("expected an existing zone to be kept, got %v", created)
+	}
+
+	if err := resourceSite_ensureDnsZone(testMeta(ops), "site", "team", "example.org"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := &models.DNSZoneSetup{AccountSlug: "team", SiteID: "site", Name: "example.org"}
+	if len(created) != 1 || !reflect.DeepEqual(created[0], expected) {
+		t.Errorf("expected %v to be created, got %v", expected, created)
+	}
+}
+
+func TestResourceSite_checkDnsZone(t *testing.T) {
+	cases := []struct {
+		zone, domain string
+		managedDns   bool
+		valid        bool
+	}{
+		{zone: "", domain: "www.example.com", valid: true},
+		{zone: "example.com", domain: "www.example.com", managedDns: true, valid: true},
+		{zone: "example.com", domain: "Example.com", managedDns: true, valid: true},
+		{zone: "example.com", domain: "www.example.com", managedDns: false, valid: false},
+		{zone: "example.com", domain: "www.notexample.com", managedDns: true, valid: false},
+		{zone: "example.com", domain: "", managedDns: true, valid: false},
+	}
+
+	for _, c := range cases {
+		err := resourceSite_checkDnsZone(c.zone, c.domain, c.managedDns)
+		if c.valid && err != nil {
+			t.Errorf("expected %+v to be valid, got %s", c, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("expected %+v to be rejected", c)
+		}
+	}
+}
+
+func TestAccSite_packagePath(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_packagePath, "apps/web"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.package_path", "apps/web"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_packagePath, "apps/docs"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.package_path", "apps/docs"),
+				),
+			},
+
+			{
+				Config: testAccSiteConfig_repo,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.package_path", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_framework(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_framework, "astro"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.framework", "astro"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_framework, "hugo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "repo.0.framework", "hugo"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_privateBuildLogs(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_privateBuildLogs, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has private logs", func() bool {
+						return site.BuildSettings.PrivateLogs
+					}),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_privateBuildLogs, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has public logs", func() bool {
+						return !site.BuildSettings.PrivateLogs
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_deployPreviews(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_deployPreviews, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "deploy_previews", "false"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_deployPreviews, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "deploy_previews", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_deployKeyPublicKey(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig_deployKeyPublicKey,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "repo.0.deploy_key_id", "netlify_deploy_key.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_rotateDeployKey(t *testing.T) {
+	var before, after models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_rotateDeployKey, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &before),
+					resource.TestCheckResourceAttrPair(resourceName, "repo.0.deploy_key_id", "netlify_deploy_key.first", "id"),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_rotateDeployKey, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &after),
+					resource.TestCheckResourceAttrPair(resourceName, "repo.0.deploy_key_id", "netlify_deploy_key.second", "id"),
+					testAccAssert("site not recreated", func() bool {
+						return before.ID == after.ID
+					}),
+					testAccAssert("repo still connected", func() bool {
+						return after.BuildSettings != nil &&
+							after.BuildSettings.RepoPath == "mitchellh/fogli" &&
+							after.BuildSettings.RepoBranch == "master"
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_allowedBranches(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig_repo,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+				),
+			},
+
+			{
+				Config: testAccSiteConfig_allowedBranches,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.allowed_branches.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "repo.0.allowed_branches.*", "staging"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "repo.0.allowed_branches.*", "develop"),
+					testAccAssert("allows production branch", func() bool {
+						for _, b := range site.BuildSettings.AllowedBranches {
+							if b == "master" {
+								return true
+							}
+						}
+						return false
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_changeProvider(t *testing.T) {
+	var before, after models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig_repo,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &before),
+				),
+			},
+
+			{
+				Config: testAccSiteConfig_repoGitlab,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &after),
+					testAccAssert("site was recreated", func() bool {
+						return before.ID != after.ID
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_privateRepoInTeam(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	team := os.Getenv("NETLIFY_TEST_ACCOUNT_SLUG")
+	repoPath := os.Getenv("NETLIFY_TEST_PRIVATE_REPO")
+	installationID := os.Getenv("NETLIFY_TEST_INSTALLATION_ID")
+	if team == "" || repoPath == "" || installationID == "" {
+		t.Skip("NETLIFY_TEST_ACCOUNT_SLUG, NETLIFY_TEST_PRIVATE_REPO and NETLIFY_TEST_INSTALLATION_ID must be set to test private repos")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_privateRepoInTeam, team, repoPath, installationID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "account_slug", team),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.installation_id", installationID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_deployKeyInTeam(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	team := os.Getenv("NETLIFY_TEST_ACCOUNT_SLUG")
+	repoPath := os.Getenv("NETLIFY_TEST_PRIVATE_REPO")
+	if team == "" || repoPath == "" {
+		t.Skip("NETLIFY_TEST_ACCOUNT_SLUG and NETLIFY_TEST_PRIVATE_REPO must be set to test deploy keys in teams")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_deployKeyInTeam, team, repoPath),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "account_slug", team),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.repo_path", repoPath),
+					resource.TestCheckResourceAttrPair(resourceName, "repo.0.deploy_key_id", "netlify_deploy_key.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+// Netlify sometimes returns only part of the repo settings of an imported
+// site linked by a deploy key, which must not show up as a diff.
+func TestAccSite_importPrivateRepo(t *testing.T) {
+	resourceName := "netlify_site.test"
+
+	team := os.Getenv("NETLIFY_TEST_ACCOUNT_SLUG")
+	repoPath := os.Getenv("NETLIFY_TEST_PRIVATE_REPO")
+	if team == "" || repoPath == "" {
+		t.Skip("NETLIFY_TEST_ACCOUNT_SLUG and NETLIFY_TEST_PRIVATE_REPO must be set to test importing private repos")
+	}
+	config := fmt.Sprintf(testAccSiteConfig_deployKeyInTeam, team, repoPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+
+			{
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccSite_renameAndMove(t *testing.T) {
+	resourceName := "netlify_site.test"
+	siteName := fmt.Sprintf("test-%s", RandStringBytes(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_updateName, siteName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", siteName),
+				),
+			},
+
+			{
+				Config:      fmt.Sprintf(testAccSiteConfig_renameAndMove, siteName+"-moved"),
+				ExpectError: regexp.MustCompile("separate apply"),
+			},
+		},
+	})
+}
+
+// Needs two teams the token owns, and moves a site from the first to the
+// second without recreating it.
+func TestAccSite_transfer(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	first := os.Getenv("NETLIFY_TEST_ACCOUNT_SLUG")
+	second := os.Getenv("NETLIFY_TEST_SECOND_ACCOUNT_SLUG")
+	if first == "" || second == "" {
+		t.Skip("NETLIFY_TEST_ACCOUNT_SLUG and NETLIFY_TEST_SECOND_ACCOUNT_SLUG must be set to test transferring sites")
+	}
+
+	var siteID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_inTeam, first),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "account_slug", first),
+					func(*terraform.State) error {
+						siteID = site.ID
+						return nil
+					},
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_inTeam, second),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "account_slug", second),
+					testAccAssert("site was transferred in place", func() bool {
+						return site.ID == siteID
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceSiteTransfer_notMoved(t *testing.T) {
+	var patched map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			json.NewDecoder(r.Body).Decode(&patched)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "site", "account_slug": "first-team"}`))
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = resourceSite_transfer(client.(*Meta), "site", "second-team")
+	if patched["account_slug"] != "second-team" {
+		t.Errorf("expected the site to be patched with the new account_slug, got %v", patched)
+	}
+	if err == nil || !strings.Contains(err.Error(), "still in team first-team") {
+		t.Errorf("expected an error when the site didn't move, got %v", err)
+	}
+}
+
+func TestAccSite_buildEnv(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_buildEnv, `NODE_VERSION = "18", API_URL = "https://api.example.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.build_env.%", "2"),
+					testAccAssert("has both variables", func() bool {
+						return site.BuildSettings.Env["NODE_VERSION"] == "18" && site.BuildSettings.Env["API_URL"] == "https://api.example.com"
+					}),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccSiteConfig_buildEnv, `NODE_VERSION = "20"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					resource.TestCheckResourceAttr(resourceName, "repo.0.build_env.%", "1"),
+					testAccAssert("removed variable was cleared", func() bool {
+						_, ok := site.BuildSettings.Env["API_URL"]
+						return !ok && site.BuildSettings.Env["NODE_VERSION"] == "20"
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSite_removeRepo(t *testing.T) {
+	var site models.Site
+	resourceName := "netlify_site.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig_repo,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has linked repo", func() bool {
+						return site.BuildSettings != nil && site.BuildSettings.RepoPath != ""
+					}),
+				),
+			},
+
+			{
+				Config: testAccSiteConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSiteExists(resourceName, &site),
+					testAccAssert("has unlinked repo", func() bool {
+						return site.BuildSettings == nil || site.BuildSettings.RepoPath == ""
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSiteExists(n string, site *models.Site) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No membership ID is set")
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetSiteParams()
+		params.SiteID = rs.Primary.ID
+		resp, err := meta.Operations.GetSite(params, meta.AuthInfo)
+		if err != nil {
+			return err
+		}
+
+		*site = *resp.Payload
+		return nil
+	}
+}
+
+func testAccCheckSiteDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "netlify_site" {
+			continue
+		}
+
+		meta := testAccProvider.Meta().(*Meta)
+		params := operations.NewGetSiteParams()
+		params.SiteID = rs.Primary.ID
+		resp, err := meta.Operations.GetSite(params, meta.AuthInfo)
+		if err == nil && resp.Payload != nil {
+			return fmt.Errorf("Site still exists: %s", rs.Primary.ID)
+		}
+
+		if err != nil {
+			if v, ok := err.(*operations.GetSiteDefault); ok && v.Code() == 404 {
+				return nil
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+var testAccSiteConfig = `
+resource "netlify_site" "test" {}
+`
+
+var testAccSiteConfig_repo = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+	}
+}
+`
+
+var testAccSiteConfig_buildEnv = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		build_env = { %s }
+	}
+}
+`
+
+var testAccSiteConfig_buildsEnabled = `
+resource "netlify_site" "test" {
+	builds_enabled = %t
+
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+	}
+}
+`
+
+var testAccSiteConfig_publicRepo = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		public_repo = true
+	}
+}
+`
+
+var testAccSiteConfig_ignoreCommand = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		ignore_command = "%s"
+	}
+}
+`
+
+var testAccSiteConfig_command = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		command = "%s"
+	}
+}
+`
+
+var testAccSiteConfig_forceSsl = `
+resource "netlify_site" "test" {
+	custom_domain = "%s"
+	force_ssl = true
+}
+`
+
+var testAccSiteConfig_customDomain = `
+resource "netlify_site" "test" {
+	custom_domain = %s
+}
+`
+
+var testAccSiteConfig_domainAliases = `
+locals {
+	domain = "%s"
+}
+
+resource "netlify_site" "test" {
+	custom_domain = local.domain
+	domain_aliases = [%s]
+	provision_certificate = %t
+}
+`
+
+var testAccSiteConfig_prerender = `
+resource "netlify_site" "test" {
+	prerender = "%s"
+}
+`
+
+var testAccSiteConfig_deletionProtection = `
+resource "netlify_site" "test" {
+	deletion_protection = %t
+}
+`
+
+var testAccSiteConfig_managedDns = `
+locals {
+	domain = "%s"
+}
+
+resource "netlify_site" "test" {
+	custom_domain = local.domain
+	managed_dns = %t
+}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = local.domain
+}
+`
+
+var testAccSiteConfig_dnsZone = `
+resource "netlify_site" "test" {
+	custom_domain = "www.%s"
+	managed_dns = true
+	dns_zone = "%[1]s"
+}
+`
+
+var testAccSiteConfig_dnsZoneSeparate = `
+resource "netlify_site" "test" {
+	custom_domain = "www.%s"
+	managed_dns = true
+}
+
+resource "netlify_dns_zone" "test" {
+	site_id = netlify_site.test.id
+	name = "%[1]s"
+}
+`
+
+var testAccSiteConfig_packagePath = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		package_path = "%s"
+	}
+}
+`
+
+var testAccSiteConfig_framework = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		framework = "%s"
+	}
+}
+`
+
+var testAccSiteConfig_privateBuildLogs = `
+resource "netlify_site" "test" {
+	private_build_logs = %t
+
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+	}
+}
+`
+
+var testAccSiteConfig_deployPreviews = `
+resource "netlify_site" "test" {
+	deploy_previews = %t
+
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+	}
+}
+`
+
+var testAccSiteConfig_deployKeyPublicKey = `
+resource "netlify_deploy_key" "test" {}
+
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		deploy_key_public_key = netlify_deploy_key.test.public_key
+	}
+}
+`
+
+var testAccSiteConfig_rotateDeployKey = `
+resource "netlify_deploy_key" "first" {}
+
+resource "netlify_deploy_key" "second" {}
+
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		deploy_key_id = netlify_deploy_key.%s.id
+	}
+}
+`
+
+var testAccSiteConfig_allowedBranches = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "github"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+		allowed_branches = ["staging", "develop"]
+	}
+}
+`
+
+var testAccSiteConfig_repoGitlab = `
+resource "netlify_site" "test" {
+	repo {
+		provider = "gitlab"
+		repo_path = "mitchellh/fogli"
+		repo_branch = "master"
+	}
+}
+`
+
+var testAccSiteConfig_privateRepoInTeam = `
+resource "netlify_site" "test" {
+	account_slug = "%s"
+
+	repo {
+		provider = "github"
+		repo_path = "%s"
+		repo_branch = "main"
+		installation_id = %s
+	}
+}
+`
+
+var testAccSiteConfig_deployKeyInTeam = `
+resource "netlify_deploy_key" "test" {}
+
+resource "netlify_site" "test" {
+	account_slug = "%s"
+
+	repo {
+		provider = "github"
+		repo_path = "%s"
+		repo_branch = "main"
+		deploy_key_id = netlify_deploy_key.test.id
+	}
+}
+`
+
+var testAccSiteConfig_updateName = `
+resource "netlify_site" "test" {
+	name = "%s"
+	allow_rename = true
+}
+`
+
+var testAccSiteConfig_inTeam = `
+resource "netlify_site" "test" {
+	account_slug = "%s"
+}
+`
+
+var testAccSiteConfig_renameAndMove = `
+resource "netlify_site" "test" {
+	name = "%s"
+	allow_rename = true
+	account_slug = "some-other-team"
+}
+`
+
+var testAccSiteConfig_rename = `
+resource "netlify_site" "test" {
+	name = "%s"
+}
+`
+
+func TestResourceSite_domainConflict(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceSite().Schema, map[string]interface{}{
+		"custom_domain":  "example.com",
+		"domain_aliases": []interface{}{"www.example.com"},
+	})
+
+	conflict := operations.NewCreateSiteDefault(422)
+	conflict.Payload = &models.Error{Message: "The domain example.com is already used by site other-site."}
+	if err := resourceSite_domainConflict(d, conflict); !regexp.MustCompile(`"example.com" is already in use.*other-site`).MatchString(err.Error()) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	alias := operations.NewUpdateSiteDefault(422)
+	alias.Payload = &models.Error{Message: "The domain www.example.com is already used by site other-site"}
+	if err := resourceSite_domainConflict(d, alias); err != alias {
+		t.Fatalf("expected an alias conflict to be returned unchanged, got %s", err)
+	}
+
+	empty := operations.NewUpdateSiteDefault(409)
+	if err := resourceSite_domainConflict(d, empty); err != empty {
+		t.Fatalf("expected a conflict without a message to be returned unchanged, got %s", err)
+	}
+
+	unrelated := operations.NewUpdateSiteDefault(422)
+	unrelated.Payload = &models.Error{Message: "name is invalid"}
+	if err := resourceSite_domainConflict(d, unrelated); err != unrelated {
+		t.Fatalf("expected unrelated error to be returned unchanged, got %s", err)
+	}
+
+	notFound := operations.NewUpdateSiteDefault(404)
+	if err := resourceSite_domainConflict(d, notFound); err != notFound {
+		t.Fatalf("expected 404 to be returned unchanged, got %s", err)
+	}
+}
+
+func TestResourceSite_dnsZone(t *testing.T) {
+	meta := testMeta(&testOperations{
+		getDNSZones: func(params *operations.GetDNSZonesParams) (*operations.GetDNSZonesOK, error) {
+			return &operations.GetDNSZonesOK{Payload: []*models.DNSZone{
+				{ID: "ample", Name: "ample.com"},
+				{ID: "example", Name: "example.com"},
+				{ID: "shop", Name: "shop.example.com"},
+			}}, nil
+		},
+	})
+
+	cases := []struct {
+		domain   string
+		expected string
+	}{
+		{"example.com", "example"},
+		{"www.example.com", "example"},
+		{"shop.example.com", "shop"},
+		{"www.Shop.Example.com", "shop"},
+		{"myshop.example.com", "example"},
+		{"sample.com", ""},
+		{"www.example.org", ""},
+	}
+
+	for _, tc := range cases {
+		zone, err := resourceSite_dnsZone(meta, &models.Site{CustomDomain: tc.domain})
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.domain, err)
+		}
+		actual := ""
+		if zone != nil {
+			actual = zone.ID
+		}
+		if actual != tc.expected {
+			t.Errorf("%s: expected zone %q, got %q", tc.domain, tc.expected, actual)
+		}
+	}
+}
+
+func TestResourceSite_dnsWarning(t *testing.T) {
+	meta := testMeta(&testOperations{
+		getDNSRecords: func(params *operations.GetDNSRecordsParams) (*operations.GetDNSRecordsOK, error) {
+			if params.ZoneID != "zone-id" {
+				t.Fatalf("expected the site's zone to be listed, got %q", params.ZoneID)
+			}
+			return &operations.GetDNSRecordsOK{Payload: []*models.DNSRecord{
+				{Hostname: "www.example.com", Type: "NETLIFY", Value: "example.netlify.app"},
+			}}, nil
+		},
+	})
+	site := func(attributes map[string]string) *schema.ResourceData {
+		attributes["name"] = "example"
+		return resourceSite().Data(&terraform.InstanceState{ID: "site-id", Attributes: attributes})
+	}
+
+	cases := []struct {
+		name       string
+		attributes map[string]string
+		warns      bool
+	}{
+		{"no custom domain", map[string]string{}, false},
+		{"managed dns", map[string]string{"custom_domain": "shop.example.com", "managed_dns": "true"}, false},
+		{"dns zone", map[string]string{"custom_domain": "shop.example.com", "dns_zone": "example.com"}, false},
+		{"record in zone", map[string]string{"custom_domain": "www.example.com", "dns_zone_id": "zone-id"}, false},
+		{"no record in zone", map[string]string{"custom_domain": "shop.example.com", "dns_zone_id": "zone-id"}, true},
+		{"external dns", map[string]string{"custom_domain": "www.example.org"}, true},
+	}
+
+	for _, tc := range cases {
+		diags := resourceSite_dnsWarning(meta, site(tc.attributes))
+		if tc.warns && (len(diags) != 1 || diags[0].Severity != diag.Warning) {
+			t.Errorf("%s: expected a warning, got %v", tc.name, diags)
+		}
+		if !tc.warns && len(diags) != 0 {
+			t.Errorf("%s: expected no warning, got %v", tc.name, diags)
+		}
+	}
+}
+
+func TestResourceSite_checkFunctionsDir(t *testing.T) {
+	cases := []struct {
+		dir          string
+		functionsDir string
+		valid        bool
+	}{
+		{"", "netlify/functions", true},
+		{"/", "netlify/functions", true},
+		{"public", "", true},
+		{"public", "netlify/functions", true},
+		{"public", "public-functions", true},
+		{"public", "public", false},
+		{"public", "public/functions", false},
+		{"/build/", "./build/functions", false},
+	}
+
+	for _, tc := range cases {
+		err := resourceSite_checkFunctionsDir(tc.dir, tc.functionsDir)
+		if (err == nil) != tc.valid {
+			t.Errorf("dir %q, functions_dir %q: expected valid=%t, got %v", tc.dir, tc.functionsDir, tc.valid, err)
+		}
+	}
+}
+
+func TestResourceSite_publishedDeploy(t *testing.T) {
+	if actual := resourceSite_publishedDeploy(&models.Site{}); len(actual) != 0 {
+		t.Errorf("expected no published deploy for an undeployed site, got %v", actual)
+	}
+
+	site := &models.Site{
+		PublishedDeploy: &models.Deploy{
+			ID:        "deploy",
+			CommitRef: "abc123",
+			Branch:    "main",
+			State:     "ready",
+			DeployURL: "https://deploy--site.netlify.app",
+		},
+	}
+	actual := resourceSite_publishedDeploy(site)
+	if len(actual) != 1 {
+		t.Fatalf("expected one published deploy, got %v", actual)
+	}
+	deploy := actual[0].(map[string]interface{})
+	if deploy["id"] != "deploy" || deploy["commit_ref"] != "abc123" || deploy["branch"] != "main" || deploy["state"] != "ready" || deploy["deploy_url"] != "https://deploy--site.netlify.app" {
+		t.Errorf("unexpected published deploy %v", deploy)
+	}
+}
+
+func TestResourceSite_findInstallationID(t *testing.T) {
+	sites := []*models.Site{
+		{BuildSettings: nil},
+		{BuildSettings: &models.RepoInfo{Provider: "github", RepoPath: "other/site"}},
+		{BuildSettings: &models.RepoInfo{Provider: "gitlab", RepoPath: "owner/site", InstallationID: 1}},
+		{BuildSettings: &models.RepoInfo{Provider: "github", RepoPath: "other/site", InstallationID: 2}},
+		{BuildSettings: &models.RepoInfo{Provider: "github", RepoPath: "Owner/site", InstallationID: 3}},
+	}
+
+	cases := []struct {
+		provider string
+		repoPath string
+		expected int64
+	}{
+		{"github", "owner/repo", 3},
+		{"github", "other/repo", 2},
+		{"gitlab", "owner/repo", 1},
+		{"github", "nobody/repo", 0},
+		{"bitbucket", "owner/repo", 0},
+	}
+
+	for _, c := range cases {
+		if actual := resourceSite_findInstallationID(sites, c.provider, c.repoPath); actual != c.expected {
+			t.Errorf("expected installation %d for %s %s, got %d", c.expected, c.provider, c.repoPath, actual)
+		}
+	}
+}
+
+func TestResourceSiteCreate_defaultAccountSlug(t *testing.T) {
+	for configured, expected := range map[string]string{"": "default-team", "own-team": "own-team"} {
+		var slug string
+		ops := &testOperations{
+			createSiteInTeam: func(params *operations.CreateSiteInTeamParams) (*operations.CreateSiteInTeamCreated, error) {
+				slug = params.AccountSlug
+				return &operations.CreateSiteInTeamCreated{Payload: &models.Site{ID: "site-id", AccountSlug: slug}}, nil
+			},
+			getSiteRaw: func(siteID string) (json.RawMessage, error) {
+				return json.RawMessage(fmt.Sprintf(`{"id": %q, "account_slug": %q}`, siteID, slug)), nil
+			},
+		}
+		meta := testMeta(ops)
+		meta.defaultAccountSlug = "default-team"
+
+		d := testResourceDataUpdate(t, resourceSite(), nil, map[string]interface{}{"account_slug": configured})
+		if err := resourceSiteCreate(d, meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if slug != expected {
+			t.Errorf("expected account_slug %q to create the site in %q, got %q", configured, expected, slug)
+		}
+		if actual := d.Get("account_slug").(string); d.Id() != "site-id" || actual != expected {
+			t.Errorf("expected site-id to be read back in %q, got %q in %q", expected, d.Id(), actual)
+		}
+	}
+}
+
+func TestResourceSiteCreate_deployKeyID(t *testing.T) {
+	for _, slug := range []string{"", "team"} {
+		var repo *models.RepoInfo
+		created := func(site *models.SiteSetup) *models.Site {
+			repo = site.Repo
+			return &models.Site{ID: "site-id"}
+		}
+		ops := &testOperations{
+			createSite: func(params *operations.CreateSiteParams) (*operations.CreateSiteCreated, error) {
+				return &operations.CreateSiteCreated{Payload: created(params.Site)}, nil
+			},
+			createSiteInTeam: func(params *operations.CreateSiteInTeamParams) (*operations.CreateSiteInTeamCreated, error) {
+				return &operations.CreateSiteInTeamCreated{Payload: created(params.Site)}, nil
+			},
+			getSiteRaw: func(siteID string) (json.RawMessage, error) {
+				return json.RawMessage(`{
+					"id": "site-id",
+					"build_settings": {"provider": "github", "repo_path": "example/private", "repo_branch": "main", "deploy_key_id": "key"}
+				}`), nil
+			},
+		}
+
+		d := testResourceDataUpdate(t, resourceSite(), nil, map[string]interface{}{
+			"account_slug": slug,
+			"repo": []interface{}{
+				map[string]interface{}{
+					"provider":      "github",
+					"repo_path":     "example/private",
+					"repo_branch":   "main",
+					"deploy_key_id": "key",
+				},
+			},
+		})
+		if err := resourceSiteCreate(d, testMeta(ops)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if repo == nil || repo.DeployKeyID != "key" {
+			t.Errorf("expected the deploy key to be sent when creating a site in account %q, got %+v", slug, repo)
+		}
+		if actual := d.Get("repo.0.deploy_key_id").(string); actual != "key" {
+			t.Errorf("expected the deploy key to be read back in account %q, got %q", slug, actual)
+		}
+	}
+}
+
+func TestResourceSite_validatePrerender(t *testing.T) {
+	for value, valid := range map[string]bool{"": true, "netlify": true, "prerender.io": false, "Netlify": false} {
+		diags := resourceSite_validatePrerender(value, cty.GetAttrPath("prerender"))
+		if valid && diags.HasError() {
+			t.Errorf("expected %q to be valid, got %v", value, diags)
+		}
+		if !valid && !diags.HasError() {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestResourceSiteDelete_deletionProtection(t *testing.T) {
+	d := resourceSite().TestResourceData()
+	d.SetId("site")
+	d.Set("deletion_protection", true)
+
+	// Deleting the site would panic on the stubs without a DeleteSite stub
+	err := resourceSiteDelete(d, testMeta(&testOperations{}))
+	if err == nil || !strings.Contains(err.Error(), "deletion_protection") {
+		t.Fatalf("expected deletion to be blocked, got %v", err)
+	}
+}
+
+func TestResourceSite_capabilities(t *testing.T) {
+	actual := resourceSite_capabilities(map[string]interface{}{
+		"title":           "Netlify Team Free",
+		"form_processing": true,
+		"split_testing":   false,
+		"rate_cents":      float64(0),
+		"bandwidth":       map[string]interface{}{"included": float64(100)},
+		"unset":           nil,
+	})
+
+	expected := map[string]interface{}{
+		"title":           "Netlify Team Free",
+		"form_processing": "true",
+		"split_testing":   "false",
+		"rate_cents":      "0",
+		"bandwidth":       `{"included":100}`,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceSite_lastDeployState(t *testing.T) {
+	meta := testMeta(&testOperations{})
+	if actual, err := resourceSite_lastDeployState(meta, "site"); err != nil || actual != "" {
+		t.Errorf("expected no lookup without read_last_deploy_states, got %q, %v", actual, err)
+	}
+
+	for _, deploys := range [][]*models.Deploy{nil, {{State: "error"}}} {
+		ops := &testOperations{
+			listSiteDeploys: func(params *operations.ListSiteDeploysParams) (*operations.ListSiteDeploysOK, error) {
+				if *params.PerPage != 1 {
+					t.Errorf("expected only the latest deploy to be requested, got per_page %d", *params.PerPage)
+				}
+				return &operations.ListSiteDeploysOK{Payload: deploys}, nil
+			},
+		}
+
+		expected := ""
+		if len(deploys) > 0 {
+			expected = deploys[0].State
+		}
+		meta := testMeta(ops)
+		meta.readLastDeployStates = true
+		actual, err := resourceSite_lastDeployState(meta, "site")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if actual != expected {
+			t.Errorf("expected last deploy state %q, got %q", expected, actual)
+		}
+	}
+}
+
+func TestResourceSite_buildHooks(t *testing.T) {
+	calls := 0
+	ops := &testOperations{
+		listSiteBuildHooks: func(params *operations.ListSiteBuildHooksParams) (*operations.ListSiteBuildHooksOK, error) {
+			calls++
+			return &operations.ListSiteBuildHooksOK{Payload: []*models.BuildHook{
+				{ID: "hook", Title: "CMS", Branch: "main", URL: "https://api.netlify.com/build_hooks/hook"},
+			}}, nil
+		},
+	}
+
+	meta := testMeta(ops)
+	if actual, err := resourceSite_buildHooks(meta, "site"); err != nil || len(actual) != 0 {
+		t.Errorf("expected no lookup without read_build_hooks, got %v, %v after %d calls", actual, err, calls)
+	}
+
+	meta.readBuildHooks = true
+	actual, err := resourceSite_buildHooks(meta, "site")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"id": "hook", "title": "CMS", "branch": "main"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceSiteDelete_envVars(t *testing.T) {
+	var deleted []string
+	ops := &testOperations{
+		getEnvVars: func(params *operations.GetEnvVarsParams) (*operations.GetEnvVarsOK, error) {
+			if params.AccountID != "team" || params.SiteID == nil || *params.SiteID != "site" {
+				t.Errorf("expected the site's environment variables to be listed, got account %q", params.AccountID)
+			}
+			return &operations.GetEnvVarsOK{Payload: []*models.EnvVar{{Key: "API_KEY"}, {Key: "GONE"}}}, nil
+		},
+		deleteEnvVar: func(params *operations.DeleteEnvVarParams) (*operations.DeleteEnvVarNoContent, error) {
+			if params.Key == "GONE" {
+				return nil, operations.NewDeleteEnvVarDefault(404)
+			}
+			deleted = append(deleted, *params.SiteID+"/"+params.Key)
+			return &operations.DeleteEnvVarNoContent{}, nil
+		},
+		deleteSite: func(params *operations.DeleteSiteParams) (*operations.DeleteSiteNoContent, error) {
+			deleted = append(deleted, params.SiteID)
+			return &operations.DeleteSiteNoContent{}, nil
+		},
+	}
+
+	d := resourceSite().TestResourceData()
+	d.SetId("site")
+	d.Set("account_slug", "team")
+	if err := resourceSiteDelete(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"site/API_KEY", "site"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected the environment variables to be deleted before the site, got %v", deleted)
+	}
+}
+
+func TestResourceSite_checkForceSsl(t *testing.T) {
+	cases := []struct {
+		forceSsl, provision, hasCertificate bool
+		domain                              string
+		valid                               bool
+	}{
+		{forceSsl: false, domain: "www.example.com", valid: true},
+		{forceSsl: true, domain: "", valid: true},
+		{forceSsl: true, domain: "www.example.com", valid: false},
+		{forceSsl: true, domain: "www.example.com", provision: true, valid: true},
+		{forceSsl: true, domain: "www.example.com", hasCertificate: true, valid: true},
+	}
+
+	for _, c := range cases {
+		err := resourceSite_checkForceSsl(c.forceSsl, c.domain, c.provision, c.hasCertificate)
+		if c.valid && err != nil {
+			t.Errorf("expected %+v to be valid, got %s", c, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("expected %+v to be rejected", c)
+		}
+	}
+}
+
+func TestResourceSiteCreate_retryRead(t *testing.T) {
+	var reads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/v1/sites/site" {
+			w.Write([]byte(`[]`))
+			return
+		}
+
+		reads++
+		if reads == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code": 503, "message": "unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"id": "site", "name": "test"}`))
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceSite().TestResourceData()
+	d.SetId("site")
+	if err := resourceSite_readAfterCreate(d, client, time.Minute); err != nil {
+		t.Fatalf("expected the read to be retried, got %s", err)
+	}
+	if reads != 2 || d.Get("name").(string) != "test" {
+		t.Errorf("expected the site to be read on the second attempt, got %d reads and name %q", reads, d.Get("name"))
+	}
+}
+
+func TestResourceSiteRead_siteInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/sites/site/ssl" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "not found"}`))
+			return
+		}
+		if r.URL.Path != "/api/v1/sites/site" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{"id": "site", "name": "test", "created_via": "ui", "plan": "nf_team_dev", "default_domain": "test.netlify.app", "custom_domain": "www.example.com",
+			"build_settings": {"provider": "github", "repo_path": "owner/repo", "repo_branch": "main", "framework": "next"}}`))
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceSite().TestResourceData()
+	d.SetId("site")
+	if err := resourceSiteRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Get("created_via").(string); actual != "ui" {
+		t.Errorf("expected created_via to be read from the site, got %q", actual)
+	}
+	if actual := d.Get("plan").(string); actual != "nf_team_dev" {
+		t.Errorf("expected plan to be read from the site, got %q", actual)
+	}
+	if d.Get("default_domain").(string) != "test.netlify.app" || d.Get("custom_domain").(string) != "www.example.com" {
+		t.Errorf("expected the default and custom domains to be read separately, got %q and %q", d.Get("default_domain"), d.Get("custom_domain"))
+	}
+	if actual := d.Get("repo.0.framework").(string); actual != "next" {
+		t.Errorf("expected the detected framework to be read, got %q", actual)
+	}
+	if actual := d.Get("tls_covered_domains").([]interface{}); len(actual) != 0 {
+		t.Errorf("expected no covered domains without a certificate, got %v", actual)
+	}
+}
+
+func TestResourceSite_tlsCoveredDomains(t *testing.T) {
+	site := &models.Site{
+		CustomDomain:  "www.example.com",
+		DomainAliases: []string{"example.com", "Docs.example.com", "a.b.example.com", "example.org"},
+	}
+
+	for _, c := range []struct {
+		certDomains []string
+		expected    []string
+	}{
+		{nil, []string{}},
+		{[]string{"www.example.com", "example.com"}, []string{"www.example.com", "example.com"}},
+		{[]string{"*.example.com"}, []string{"www.example.com", "Docs.example.com"}},
+		{[]string{"EXAMPLE.ORG"}, []string{"example.org"}},
+	} {
+		if actual := resourceSite_tlsCoveredDomains(site, c.certDomains); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("expected %v to cover %v, got %v", c.certDomains, c.expected, actual)
+		}
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	for err, expected := range map[error]bool{
+		operations.NewGetSiteDefault(503): true,
+		operations.NewGetSiteDefault(404): false,
+		errors.New("connection reset"):    false,
+	} {
+		if actual := isTransientError(err); actual != expected {
+			t.Errorf("expected %v to be transient: %t, got %t", err, expected, actual)
+		}
+	}
+}
+
+func TestResourceSite_buildEnvPatch(t *testing.T) {
+	actual := resourceSite_buildEnvPatch(
+		map[string]interface{}{"NODE_VERSION": "18", "API_URL": "https://api.example.com"},
+		map[string]interface{}{"NODE_VERSION": "20"},
+	)
+	expected := map[string]interface{}{"NODE_VERSION": "20", "API_URL": nil}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceSite_patchBuildSettings(t *testing.T) {
+	var body map[string]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "site"}`))
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, publicRepo := range []cty.Value{cty.NullVal(cty.Bool), cty.False} {
+		d := resourceSite().Data(&terraform.InstanceState{
+			ID: "site",
+			Attributes: map[string]string{
+				"repo.#":             "1",
+				"repo.0.command":     "npm run build",
+				"repo.0.public_repo": "true",
+			},
+			RawConfig: cty.ObjectVal(map[string]cty.Value{
+				"repo": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{"public_repo": publicRepo}),
+				}),
+			}),
+		})
+		if err := resourceSite_patchBuildSettings(d, client.(*Meta)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, sent := body["build_settings"]["public_repo"]
+		if publicRepo.IsNull() && sent {
+			t.Errorf("expected an unset public_repo not to be sent, got %v", body["build_settings"])
+		}
+		if !publicRepo.IsNull() && !sent {
+			t.Errorf("expected a configured public_repo to be sent, got %v", body["build_settings"])
+		}
+		if _, ok := body["build_settings"]["env"]; ok {
+			t.Errorf("expected an unset build_env not to be sent, got %v", body["build_settings"])
+		}
+	}
+
+	d := resourceSite().Data(&terraform.InstanceState{
+		ID: "site",
+		Attributes: map[string]string{
+			"repo.#":                        "1",
+			"repo.0.build_env.%":            "1",
+			"repo.0.build_env.NODE_VERSION": "20",
+		},
+	})
+	if err := resourceSite_patchBuildSettings(d, client.(*Meta)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if env, ok := body["build_settings"]["env"].(map[string]interface{}); !ok || env["NODE_VERSION"] != "20" {
+		t.Errorf("expected a configured build_env to be sent, got %v", body["build_settings"])
+	}
+
+	// An unset framework holds the detected preset, which mustn't be pinned
+	for framework, configured := range map[string]cty.Value{"next": cty.NullVal(cty.String), "astro": cty.StringVal("astro")} {
+		d := resourceSite().Data(&terraform.InstanceState{
+			ID: "site",
+			Attributes: map[string]string{
+				"repo.#":           "1",
+				"repo.0.framework": framework,
+			},
+			RawConfig: cty.ObjectVal(map[string]cty.Value{
+				"repo": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{"framework": configured}),
+				}),
+			}),
+		})
+		if err := resourceSite_patchBuildSettings(d, client.(*Meta)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		sent, ok := body["build_settings"]["framework"]
+		if configured.IsNull() && ok {
+			t.Errorf("expected the detected framework not to be sent, got %v", body["build_settings"])
+		}
+		if !configured.IsNull() && sent != framework {
+			t.Errorf("expected the configured framework to be sent, got %v", body["build_settings"])
+		}
+	}
+}
+
+func TestResourceSiteUpdate_functionsDirRemoved(t *testing.T) {
+	var settings []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPatch {
+			var body map[string]map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["build_settings"] != nil {
+				settings = append(settings, body["build_settings"])
+			}
+		}
+		if r.URL.Path != "/api/v1/sites/site" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{"id": "site", "name": "test"}`))
+	}))
+	defer server.Close()
+
+	config := Config{Token: "token", BaseURL: server.URL + "/api/v1"}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	repo := map[string]interface{}{
+		"provider":    "github",
+		"repo_path":   "owner/repo",
+		"repo_branch": "main",
+	}
+	state := resourceSite().Data(nil)
+	state.SetId("site")
+	state.Set("name", "test")
+	state.Set("builds_enabled", true)
+	state.Set("deploy_previews", true)
+	state.Set("repo", []interface{}{map[string]interface{}{
+		"provider":      "github",
+		"repo_path":     "owner/repo",
+		"repo_branch":   "main",
+		"functions_dir": "functions",
+	}})
+	d := testResourceDataUpdate(t, resourceSite(), state.State(), map[string]interface{}{
+		"name": "test",
+		"repo": []interface{}{repo},
+	})
+	if !d.HasChange("repo.0.functions_dir") {
+		t.Fatalf("expected functions_dir to be removed")
+	}
+
+	if err := resourceSiteUpdate(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(settings) != 1 {
+		t.Fatalf("expected the build settings to be patched once, got %v", settings)
+	}
+	if v, ok := settings[0]["functions_dir"]; !ok || v != nil {
+		t.Errorf("expected functions_dir to be cleared, got %v", settings[0])
+	}
+}
+
+func TestResourceSite_buildEnv(t *testing.T) {
+	remote := map[string]string{"NODE_VERSION": "18", "SET_IN_UI": "true"}
+
+	d := resourceSite().TestResourceData()
+	if actual := resourceSite_buildEnv(d, remote); len(actual) != 0 {
+		t.Errorf("expected an unmanaged build environment to be left out, got %v", actual)
+	}
+
+	d.Set("repo", []interface{}{
+		map[string]interface{}{"build_env": map[string]interface{}{"NODE_VERSION": "18"}},
+	})
+	expected := map[string]interface{}{"NODE_VERSION": "18", "SET_IN_UI": "true"}
+	if actual := resourceSite_buildEnv(d, remote); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceSite_accountType(t *testing.T) {
+	calls := 0
+	ops := &testOperations{
+		getAccount: func(params *operations.GetAccountParams) (*operations.GetAccountOK, error) {
+			calls++
+			return &operations.GetAccountOK{Payload: []*models.AccountMembership{{Slug: params.AccountID, Type: "pro"}}}, nil
+		},
+	}
+
+	meta := testMeta(ops)
+	if actual, err := resourceSite_accountType(meta, "team"); err != nil || actual != "" || calls != 0 {
+		t.Errorf("expected no lookup without read_account_types, got %q, %v after %d calls", actual, err, calls)
+	}
+
+	meta.readAccountTypes = true
+	for i := 0; i < 2; i++ {
+		if actual, err := resourceSite_accountType(meta, "team"); err != nil || actual != "pro" {
+			t.Errorf("expected account type pro, got %q, %v", actual, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the team to be looked up once, got %d", calls)
+	}
+}
+
+func TestResourceSite_repoIdentity(t *testing.T) {
+	d := resourceSite().TestResourceData()
+	d.Set("repo", []interface{}{map[string]interface{}{
+		"provider":    "github",
+		"repo_path":   "owner/state",
+		"repo_branch": "main",
+	}})
+
+	cases := []struct {
+		settings                       models.RepoInfo
+		provider, repoPath, repoBranch string
+	}{
+		{
+			settings: models.RepoInfo{Provider: "gitlab", RepoPath: "owner/repo", RepoBranch: "dev"},
+			provider: "gitlab", repoPath: "owner/repo", repoBranch: "dev",
+		},
+		{
+			settings: models.RepoInfo{DeployKeyID: "key", RepoURL: "https://gitlab.com/owner/repo.git"},
+			provider: "gitlab", repoPath: "owner/repo", repoBranch: "main",
+		},
+		{
+			settings: models.RepoInfo{DeployKeyID: "key"},
+			provider: "github", repoPath: "owner/state", repoBranch: "main",
+		},
+	}
+
+	for _, c := range cases {
+		provider, repoPath, repoBranch := resourceSite_repoIdentity(d, &c.settings)
+		if provider != c.provider || repoPath != c.repoPath || repoBranch != c.repoBranch {
+			t.Errorf("expected %+v to give %s %s@%s, got %s %s@%s", c.settings, c.provider, c.repoPath, c.repoBranch, provider, repoPath, repoBranch)
+		}
+	}
 }
-`
@@ -0,0 +1,173 @@
+package netlify
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Provisions the TLS certificate of a site, either by uploading one (such as
+// a wildcard certificate issued elsewhere) or by asking Netlify to provision a
+// Let's Encrypt certificate for the site's domains. Netlify has no endpoint to
+// remove a certificate, so destroying the resource only removes it from state.
+func resourceSslCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSslCertificateCreate,
+		Read:   resourceSslCertificateRead,
+		Update: resourceSslCertificateUpdate,
+		Delete: resourceSslCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"certificate": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"certificate", "private_key"},
+				Description:  "The PEM encoded certificate to upload. If unset, Netlify provisions a Let's Encrypt certificate for the site's domains instead.",
+			},
+
+			"private_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"certificate", "private_key"},
+				Description:  "The PEM encoded private key of `certificate`.",
+			},
+
+			"ca_certificates": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"certificate"},
+				Description:  "The PEM encoded intermediate certificates of `certificate`.",
+			},
+
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state Netlify reports for the certificate. A Let's Encrypt certificate stays pending until the site's domains resolve to Netlify.",
+			},
+
+			"domains": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The domains the certificate covers, including wildcard domains such as `*.example.com`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSslCertificateCreate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	if err := resourceSslCertificate_provision(d, meta); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("site_id").(string))
+	return resourceSslCertificateRead(d, metaRaw)
+}
+
+func resourceSslCertificateRead(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	params := operations.NewShowSiteTLSCertificateParams()
+	params.SiteID = d.Id()
+	resp, err := meta.Operations.ShowSiteTLSCertificate(params, meta.AuthInfo)
+	if err != nil {
+		if v, ok := err.(*operations.ShowSiteTLSCertificateDefault); ok && v.Code() == 404 {
+			return resourceSslCertificate_notFound(d, meta)
+		}
+
+		return err
+	}
+
+	cert := resp.Payload
+	d.Set("site_id", d.Id())
+	d.Set("state", cert.State)
+	d.Set("domains", cert.Domains)
+	d.Set("expires_at", cert.ExpiresAt)
+
+	return nil
+}
+
+// A requested Let's Encrypt certificate only shows up once it has been
+// issued, which can take as long as the site's domains take to resolve to
+// Netlify, so it stays pending. An uploaded certificate that is missing was
+// removed remotely and is uploaded again, and so is any certificate of a site
+// that was removed.
+func resourceSslCertificate_notFound(d *schema.ResourceData, meta *Meta) error {
+	params := operations.NewGetSiteParams()
+	params.SiteID = d.Id()
+	if _, err := meta.Operations.GetSite(params, meta.AuthInfo); err != nil {
+		if v, ok := err.(*operations.GetSiteDefault); ok && v.Code() == 404 {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	if _, ok := d.GetOk("certificate"); ok {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("site_id", d.Id())
+	d.Set("state", "pending")
+	d.Set("domains", []string{})
+	return nil
+}
+
+func resourceSslCertificateUpdate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	if d.HasChanges("certificate", "private_key", "ca_certificates") {
+		if err := resourceSslCertificate_provision(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return resourceSslCertificateRead(d, metaRaw)
+}
+
+func resourceSslCertificateDelete(d *schema.ResourceData, metaRaw interface{}) error {
+	return nil
+}
+
+// Uploads the configured certificate, or requests a Let's Encrypt one if
+// none is configured.
+func resourceSslCertificate_provision(d *schema.ResourceData, meta *Meta) error {
+	v, ok := d.GetOk("certificate")
+	if !ok {
+		return resourceSite_provisionCertificate(meta, d.Get("site_id").(string))
+	}
+
+	params := operations.NewProvisionSiteTLSCertificateParams()
+	params.SiteID = d.Get("site_id").(string)
+	certificate, key := v.(string), d.Get("private_key").(string)
+	params.Certificate = &certificate
+	params.Key = &key
+	if v, ok := d.GetOk("ca_certificates"); ok {
+		ca := v.(string)
+		params.CaCertificates = &ca
+	}
+
+	_, err := meta.Operations.ProvisionSiteTLSCertificate(params, meta.AuthInfo)
+	if err != nil {
+		return fmt.Errorf("Error uploading the certificate of site %s: %s", params.SiteID, err)
+	}
+	return nil
+}
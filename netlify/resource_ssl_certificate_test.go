@@ -0,0 +1,154 @@
+package netlify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Uploads a real certificate, so it needs the paths of one and its key for a
+// domain the test can point a site at, e.g. a wildcard certificate for
+// `*.example.com`.
+func TestAccSslCertificate_upload(t *testing.T) {
+	domain := os.Getenv("NETLIFY_TEST_CERTIFICATE_DOMAIN")
+	if domain == "" || os.Getenv("NETLIFY_TEST_CERTIFICATE") == "" || os.Getenv("NETLIFY_TEST_CERTIFICATE_KEY") == "" {
+		t.Skip("NETLIFY_TEST_CERTIFICATE_DOMAIN, NETLIFY_TEST_CERTIFICATE and NETLIFY_TEST_CERTIFICATE_KEY must be set to test uploading certificates")
+	}
+	resourceName := "netlify_ssl_certificate.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckSiteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSslCertificateConfig, domain, os.Getenv("NETLIFY_TEST_CERTIFICATE"), os.Getenv("NETLIFY_TEST_CERTIFICATE_KEY")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "site_id", "netlify_site.test", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "domains.0"),
+					resource.TestCheckResourceAttrSet(resourceName, "expires_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceSslCertificateRead(t *testing.T) {
+	ops := &testOperations{
+		showSiteTLSCertificate: func(params *operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error) {
+			return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{
+				State:   "custom",
+				Domains: []string{"example.com", "*.example.com"},
+			}}, nil
+		},
+	}
+
+	d := resourceSslCertificate().TestResourceData()
+	d.SetId("site")
+	if err := resourceSslCertificateRead(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual := d.Get("site_id").(string); actual != "site" {
+		t.Errorf("expected site_id to be read from the ID, got %q", actual)
+	}
+	if actual := d.Get("domains.1").(string); actual != "*.example.com" {
+		t.Errorf("expected the wildcard domain to be read, got %q", actual)
+	}
+}
+
+func TestResourceSslCertificateRead_notFound(t *testing.T) {
+	siteExists := true
+	ops := &testOperations{
+		showSiteTLSCertificate: func(params *operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error) {
+			return nil, operations.NewShowSiteTLSCertificateDefault(404)
+		},
+		getSite: func(params *operations.GetSiteParams) (*operations.GetSiteOK, error) {
+			if !siteExists {
+				return nil, operations.NewGetSiteDefault(404)
+			}
+			return &operations.GetSiteOK{Payload: &models.Site{ID: params.SiteID}}, nil
+		},
+	}
+
+	// A requested certificate that hasn't been issued yet stays pending, also
+	// on later refreshes
+	for _, isNew := range []bool{true, false} {
+		d := resourceSslCertificate().TestResourceData()
+		d.SetId("site")
+		if isNew {
+			d.MarkNewResource()
+		}
+		if err := resourceSslCertificateRead(d, testMeta(ops)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if d.Id() != "site" || d.Get("state").(string) != "pending" {
+			t.Errorf("expected a requested certificate to be pending, got ID %q and state %q", d.Id(), d.Get("state"))
+		}
+	}
+
+	// A missing uploaded certificate is uploaded again
+	d := resourceSslCertificate().TestResourceData()
+	d.SetId("site")
+	d.Set("certificate", "cert")
+	d.Set("private_key", "key")
+	if err := resourceSslCertificateRead(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected a removed uploaded certificate to be dropped from state, got ID %q", d.Id())
+	}
+
+	// So is the certificate of a removed site
+	siteExists = false
+	d = resourceSslCertificate().TestResourceData()
+	d.SetId("site")
+	if err := resourceSslCertificateRead(d, testMeta(ops)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the certificate of a removed site to be dropped from state, got ID %q", d.Id())
+	}
+}
+
+func TestResourceSslCertificate_provisionLog(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_NETLIFY", "")
+	t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+
+	out := testLoggedRequests(t, func(meta *Meta) {
+		d := resourceSslCertificate().TestResourceData()
+		d.Set("site_id", "site")
+		d.Set("certificate", "cert-secret")
+		d.Set("private_key", "key-secret")
+		d.Set("ca_certificates", "ca-secret")
+		if err := resourceSslCertificate_provision(d, meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	})
+
+	if !strings.Contains(out, "/sites/site/ssl") {
+		t.Fatalf("expected the provision request to be logged:\n%s", out)
+	}
+	for _, secret := range []string{"cert-secret", "key-secret", "ca-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q:\n%s", secret, out)
+		}
+	}
+}
+
+var testAccSslCertificateConfig = `
+resource "netlify_site" "test" {
+	custom_domain = "%s"
+}
+
+resource "netlify_ssl_certificate" "test" {
+	site_id = netlify_site.test.id
+	certificate = file("%s")
+	private_key = file("%s")
+}
+`
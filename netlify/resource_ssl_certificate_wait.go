@@ -0,0 +1,132 @@
+package netlify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// Waits on create until the TLS certificate of a site has been issued, so
+// resources that need it, such as a netlify_site with force_ssl, can
+// depends_on it. It manages nothing on Netlify, so destroying the resource
+// only removes it from state.
+func resourceSslCertificateWait() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSslCertificateWaitCreate,
+		Read:   resourceSslCertificateWaitRead,
+		Delete: resourceSslCertificateWaitDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the certificate, `issued` for a Let's Encrypt certificate or `custom` for an uploaded one.",
+			},
+
+			"domains": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSslCertificateWaitCreate(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	siteID := d.Get("site_id").(string)
+	if err := resourceSslCertificateWait_wait(meta, siteID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return fmt.Errorf("Error waiting for the certificate of site %s to be issued: %s", siteID, err)
+	}
+
+	d.SetId(siteID)
+	return resourceSslCertificateWaitRead(d, metaRaw)
+}
+
+func resourceSslCertificateWaitRead(d *schema.ResourceData, metaRaw interface{}) error {
+	meta := metaRaw.(*Meta)
+	cert, err := resourceSslCertificateWait_show(meta, d.Id())
+	if err != nil {
+		return err
+	}
+
+	// If the site or its certificate was removed remotely, wait again
+	if cert == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("site_id", d.Id())
+	d.Set("state", cert.State)
+	d.Set("domains", cert.Domains)
+	d.Set("expires_at", cert.ExpiresAt)
+
+	return nil
+}
+
+func resourceSslCertificateWaitDelete(d *schema.ResourceData, metaRaw interface{}) error {
+	return nil
+}
+
+// Polls the certificate of the site until it is ready to serve. A requested
+// certificate only shows up once Netlify starts provisioning it, so a missing
+// one counts as pending, and one Netlify failed to issue ends the wait.
+func resourceSslCertificateWait_wait(meta *Meta, siteID string, timeout time.Duration) error {
+	conf := &resource.StateChangeConf{
+		Pending: []string{"missing", "new", "pending", "verifying", "verified"},
+		Target:  []string{"issued", "custom"},
+		Timeout: timeout,
+		Refresh: func() (interface{}, string, error) {
+			cert, err := resourceSslCertificateWait_show(meta, siteID)
+			if err != nil {
+				return nil, "", err
+			}
+			if cert == nil {
+				return "missing", "missing", nil
+			}
+			switch cert.State {
+			case "error", "failed":
+				return nil, "", fmt.Errorf("Netlify couldn't issue the certificate, its state is %q", cert.State)
+			}
+			return cert, cert.State, nil
+		},
+	}
+
+	_, err := conf.WaitForState()
+	return err
+}
+
+// Returns the certificate of the site, or nil if it has none.
+func resourceSslCertificateWait_show(meta *Meta, siteID string) (*models.SniCertificate, error) {
+	params := operations.NewShowSiteTLSCertificateParams()
+	params.SiteID = siteID
+	resp, err := meta.Operations.ShowSiteTLSCertificate(params, meta.AuthInfo)
+	if err != nil {
+		if v, ok := err.(*operations.ShowSiteTLSCertificateDefault); ok && v.Code() == 404 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Payload, nil
+}
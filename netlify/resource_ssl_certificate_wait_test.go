@@ -0,0 +1,79 @@
+package netlify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+func TestResourceSslCertificateWait_wait(t *testing.T) {
+	var polls int
+	ops := &testOperations{
+		showSiteTLSCertificate: func(params *operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error) {
+			polls++
+			switch polls {
+			case 1:
+				return nil, operations.NewShowSiteTLSCertificateDefault(404)
+			case 2:
+				return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{State: "pending"}}, nil
+			default:
+				return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{State: "issued"}}, nil
+			}
+		},
+	}
+
+	if err := resourceSslCertificateWait_wait(testMeta(ops), "site", time.Minute); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if polls != 3 {
+		t.Errorf("expected to wait until the certificate was issued, got %d polls", polls)
+	}
+}
+
+func TestResourceSslCertificateWait_timeout(t *testing.T) {
+	ops := &testOperations{
+		showSiteTLSCertificate: func(params *operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error) {
+			return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{State: "pending"}}, nil
+		},
+	}
+
+	if err := resourceSslCertificateWait_wait(testMeta(ops), "site", time.Second); err == nil {
+		t.Errorf("expected a certificate that stays pending to time out")
+	}
+}
+
+func TestResourceSslCertificateWait_uploaded(t *testing.T) {
+	ops := &testOperations{
+		showSiteTLSCertificate: func(params *operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error) {
+			return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{State: "custom"}}, nil
+		},
+	}
+
+	if err := resourceSslCertificateWait_wait(testMeta(ops), "site", time.Minute); err != nil {
+		t.Errorf("expected an uploaded certificate to be ready, got %s", err)
+	}
+}
+
+func TestResourceSslCertificateWait_failed(t *testing.T) {
+	var polls int
+	ops := &testOperations{
+		showSiteTLSCertificate: func(params *operations.ShowSiteTLSCertificateParams) (*operations.ShowSiteTLSCertificateOK, error) {
+			polls++
+			if polls == 1 {
+				return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{State: "verifying"}}, nil
+			}
+			return &operations.ShowSiteTLSCertificateOK{Payload: &models.SniCertificate{State: "failed"}}, nil
+		},
+	}
+
+	err := resourceSslCertificateWait_wait(testMeta(ops), "site", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), `"failed"`) {
+		t.Fatalf("expected a failed certificate to end the wait with its state, got %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("expected to stop polling once the certificate failed, got %d polls", polls)
+	}
+}
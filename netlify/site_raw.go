@@ -0,0 +1,151 @@
+package netlify
+
+import (
+	"encoding/json"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/netlify/open-api/v2/go/models"
+	"github.com/netlify/open-api/v2/go/plumbing/operations"
+)
+
+// The generated models only know about part of the site API: every field is
+// tagged with omitempty, so zero values such as `false` or `""` can never be
+// sent through UpdateSite, and some build settings are missing entirely. The
+// helpers here talk to the same endpoints with untyped bodies instead.
+
+// The requests that bypass the generated operations. They go through
+// Meta.raw, which tests can replace the same way as Meta.Operations.
+type rawOperations interface {
+	// Sends a partial update of a site, with the error types of UpdateSite.
+	PatchSiteRaw(siteID string, body map[string]interface{}) error
+
+	// Fetches a site as raw JSON, with the error types of GetSite.
+	GetSiteRaw(siteID string) (json.RawMessage, error)
+
+	// Fetches the current user without decoding it, with the error types of
+	// GetCurrentUser.
+	GetCurrentUserRaw() error
+}
+
+// Submits the raw requests through the client's transport.
+type transportRawOperations struct {
+	transport runtime.ClientTransport
+	authInfo  runtime.ClientAuthInfoWriter
+}
+
+type sitePatchParams struct {
+	SiteID string
+	Body   map[string]interface{}
+}
+
+func (p *sitePatchParams) WriteToRequest(r runtime.ClientRequest, _ strfmt.Registry) error {
+	if err := r.SetBodyParam(p.Body); err != nil {
+		return err
+	}
+
+	return r.SetPathParam("site_id", p.SiteID)
+}
+
+// Sends a partial update of the site with the given body, using the same
+// operation (and therefore the same error types) as UpdateSite.
+func patchSite(meta *Meta, siteID string, body map[string]interface{}) error {
+	return meta.raw.PatchSiteRaw(siteID, body)
+}
+
+func (o *transportRawOperations) PatchSiteRaw(siteID string, body map[string]interface{}) error {
+	_, err := o.transport.Submit(&runtime.ClientOperation{
+		ID:                 "updateSite",
+		Method:             "PATCH",
+		PathPattern:        "/sites/{site_id}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             &sitePatchParams{SiteID: siteID, Body: body},
+		Reader:             &operations.UpdateSiteReader{},
+		AuthInfo:           o.authInfo,
+	})
+	return err
+}
+
+// Reads successful responses as raw JSON, and everything else the same way
+// GetSite does so callers can keep checking for *operations.GetSiteDefault.
+type rawSiteReader struct{}
+
+func (rawSiteReader) ReadResponse(resp runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	if resp.Code() != 200 {
+		return (&operations.GetSiteReader{}).ReadResponse(resp, consumer)
+	}
+
+	var raw json.RawMessage
+	if err := consumer.Consume(resp.Body(), &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// Fetches a site as raw JSON.
+func getSiteRaw(meta *Meta, siteID string) (json.RawMessage, error) {
+	return meta.raw.GetSiteRaw(siteID)
+}
+
+func (o *transportRawOperations) GetSiteRaw(siteID string) (json.RawMessage, error) {
+	params := operations.NewGetSiteParams()
+	params.SiteID = siteID
+	result, err := o.transport.Submit(&runtime.ClientOperation{
+		ID:                 "getSite",
+		Method:             "GET",
+		PathPattern:        "/sites/{site_id}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             rawSiteReader{},
+		AuthInfo:           o.authInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(json.RawMessage), nil
+}
+
+// The parts of a site that models.Site is missing. The build settings are
+// kept raw so fields missing from models.RepoInfo can still be read.
+type siteRawFields struct {
+	BuildSettings map[string]interface{} `json:"build_settings"`
+	CreatedVia    string                 `json:"created_via"`
+	DefaultDomain string                 `json:"default_domain"`
+}
+
+// Fetches a site, returning both the typed model and the fields it is missing.
+func getSite(meta *Meta, siteID string) (*models.Site, *siteRawFields, error) {
+	raw, err := getSiteRaw(meta, siteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	site := &models.Site{}
+	if err := json.Unmarshal(raw, site); err != nil {
+		return nil, nil, err
+	}
+
+	fields := &siteRawFields{}
+	if err := json.Unmarshal(raw, fields); err != nil {
+		return nil, nil, err
+	}
+	if fields.BuildSettings == nil {
+		fields.BuildSettings = map[string]interface{}{}
+	}
+
+	return site, fields, nil
+}
+
+// Returns the site's Netlify subdomain (e.g. `mysite.netlify.app`), which
+// models.Site doesn't have.
+func getSiteDefaultDomain(meta *Meta, siteID string) (string, error) {
+	_, fields, err := getSite(meta, siteID)
+	if err != nil {
+		return "", err
+	}
+	return fields.DefaultDomain, nil
+}